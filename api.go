@@ -1,17 +1,18 @@
-// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2025
+// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2026
 // Matthew Wilson and Synesis Information Systems. All rights reserved. Use
 // of this source code is governed by a BSD-style license that can be found
 // in the LICENSE file.
 
 /*
  * Created: 17th June 2005
- * Updated: 24th February 2025
+ * Updated: 18th October 2026
  */
 
 package shwild
 
 import (
 	"fmt"
+	"io"
 )
 
 /* /////////////////////////////////////////////////////////////////////////
@@ -20,15 +21,52 @@ import (
 
 type patternBehaviour int
 
+// The empty pattern is the zero value, so that the zero value of
+// CompiledPattern - as when a field of a configuration structure is absent
+// or null - is the empty pattern, which matches only the empty string.
 const (
+	_PB_EmptyPattern   patternBehaviour = 0
 	_PB_RegularPattern patternBehaviour = 1 << iota
-	_PB_EmptyPattern   patternBehaviour = 1 << iota
 	_PB_AllWildPattern patternBehaviour = 1 << iota
 )
 
+// PartialResult indicates the result of matching a prefix of a string, as
+// obtained from CompiledPattern.MatchPartial().
+type PartialResult int
+
+const (
+	// Neither the prefix nor any extension of it matches
+	NoMatch PartialResult = iota
+
+	// The prefix matches (and extensions of it may also match)
+	Matched
+
+	// The prefix does not match, but some extension of it may
+	NeedMore
+)
+
+func (r PartialResult) String() string {
+
+	switch r {
+
+	case NoMatch:
+		return "NoMatch"
+	case Matched:
+		return "Matched"
+	case NeedMore:
+		return "NeedMore"
+	}
+
+	return fmt.Sprintf("<%T %d>", r, r)
+}
+
+// CompiledPattern is a pattern compiled by Compile(). The zero value is the
+// empty pattern, which matches only the empty string.
 type CompiledPattern struct {
 	Pattern   string
-	matchers  []matcher
+	flags     uint64
+	separator rune
+	prog      *program
 	behaviour patternBehaviour
 }
 
@@ -44,7 +82,87 @@ func (cp CompiledPattern) Match(s string) (bool, error) {
 		return true, nil
 	case _PB_RegularPattern:
 
-		return match_from_compiled_(cp.matchers, s)
+		return match_from_compiled_(cp.prog, s)
+	default:
+
+		msg := fmt.Sprintf("VIOLATION: unrecognised CompiledPattern.behaviour %d", cp.behaviour)
+
+		panic(msg)
+	}
+}
+
+// MatchBytes matches b against the pattern, exactly as Match() matches
+// string(b), but without conversion.
+func (cp CompiledPattern) MatchBytes(b []byte) (bool, error) {
+
+	switch cp.behaviour {
+
+	case _PB_EmptyPattern:
+
+		return 0 == len(b), nil
+	case _PB_AllWildPattern:
+
+		return true, nil
+	case _PB_RegularPattern:
+
+		return match_input(cp.prog, b), nil
+	default:
+
+		msg := fmt.Sprintf("VIOLATION: unrecognised CompiledPattern.behaviour %d", cp.behaviour)
+
+		panic(msg)
+	}
+}
+
+// MatchRunes matches rs against the pattern, exactly as Match() matches
+// string(rs), but without conversion.
+func (cp CompiledPattern) MatchRunes(rs []rune) (bool, error) {
+
+	switch cp.behaviour {
+
+	case _PB_EmptyPattern:
+
+		return 0 == len(rs), nil
+	case _PB_AllWildPattern:
+
+		return true, nil
+	case _PB_RegularPattern:
+
+		return cp.prog.match_runes(rs), nil
+	default:
+
+		msg := fmt.Sprintf("VIOLATION: unrecognised CompiledPattern.behaviour %d", cp.behaviour)
+
+		panic(msg)
+	}
+}
+
+// MatchReader matches the characters read from rr, up to io.EOF, against
+// the pattern, without buffering them. Reading stops as soon as the result
+// is known, so rr may not be read to its end. An error other than io.EOF
+// from rr is returned. An invalid UTF-8 sequence is read (by rr) as
+// utf8.RuneError, and so is matched as that character rather than as the
+// invalid byte.
+func (cp CompiledPattern) MatchReader(rr io.RuneReader) (bool, error) {
+
+	switch cp.behaviour {
+
+	case _PB_EmptyPattern:
+
+		_, _, err := rr.ReadRune()
+
+		if io.EOF == err {
+
+			return true, nil
+		}
+
+		return false, err
+	case _PB_AllWildPattern:
+
+		return true, nil
+	case _PB_RegularPattern:
+
+		return cp.prog.match_reader(rr)
 	default:
 
 		msg := fmt.Sprintf("VIOLATION: unrecognised CompiledPattern.behaviour %d", cp.behaviour)
@@ -53,6 +171,186 @@ func (cp CompiledPattern) Match(s string) (bool, error) {
 	}
 }
 
+// FindSubmatch matches s against the pattern and, if it matches, obtains
+// a slice whose first element is s and whose remaining elements are the
+// substrings consumed by each wildcard - ?, *, **, range and not-range - in
+// order of their position in the pattern. A wildcard in an alternation
+// branch that does not participate in the match has an empty substring.
+// Where the match is ambiguous, as in "*-*" against "a-b-c", stars are
+// greedy.
+func (cp CompiledPattern) FindSubmatch(s string) ([]string, bool) {
+
+	indexes, matched := cp.FindSubmatchIndex(s)
+
+	if !matched {
+
+		return nil, false
+	}
+
+	submatches := make([]string, len(indexes)/2)
+
+	for i := range submatches {
+
+		if beg, end := indexes[2*i], indexes[2*i+1]; beg >= 0 {
+
+			submatches[i] = s[beg:end]
+		}
+	}
+
+	return submatches, true
+}
+
+// FindSubmatchIndex is as FindSubmatch, but obtains pairs of byte offsets
+// into s - s[indexes[2*n]:indexes[2*n+1]] being the n-th submatch -
+// rather than substrings. A wildcard that does not participate in the
+// match has the offsets -1.
+func (cp CompiledPattern) FindSubmatchIndex(s string) ([]int, bool) {
+
+	switch cp.behaviour {
+
+	case _PB_EmptyPattern:
+
+		if 0 != len(s) {
+
+			return nil, false
+		}
+
+		return []int{0, 0}, true
+	case _PB_AllWildPattern:
+
+		// the first star is greedy, and the remainder match nothing
+
+		indexes := []int{0, len(s)}
+
+		for i := 0; len(cp.Pattern) != i; i++ {
+
+			if 0 == i {
+
+				indexes = append(indexes, 0, len(s))
+			} else {
+
+				indexes = append(indexes, len(s), len(s))
+			}
+		}
+
+		return indexes, true
+	case _PB_RegularPattern:
+
+		// the captures are obtained only once the subject is known to
+		// match, which is determined far more cheaply
+
+		if !cp.prog.match(s) {
+
+			return nil, false
+		}
+
+		return cp.prog.capturing_program().match_captures(s)
+	default:
+
+		msg := fmt.Sprintf("VIOLATION: unrecognised CompiledPattern.behaviour %d", cp.behaviour)
+
+		panic(msg)
+	}
+}
+
+// MatchPartial matches prefix against the pattern, determining whether
+// prefix matches, whether some extension of it may yet match - which
+// allows, for example, a directory to be skipped when no path within it
+// may match - or neither.
+func (cp CompiledPattern) MatchPartial(prefix string) PartialResult {
+
+	switch cp.behaviour {
+
+	case _PB_EmptyPattern:
+
+		if 0 == len(prefix) {
+
+			return Matched
+		}
+
+		return NoMatch
+	case _PB_AllWildPattern:
+
+		return Matched
+	case _PB_RegularPattern:
+
+		return cp.prog.match_partial(prefix)
+	default:
+
+		msg := fmt.Sprintf("VIOLATION: unrecognised CompiledPattern.behaviour %d", cp.behaviour)
+
+		panic(msg)
+	}
+}
+
+// MatchPrefix determines whether prefix, or some extension of it, may
+// match the pattern.
+func (cp CompiledPattern) MatchPrefix(prefix string) bool {
+
+	return NoMatch != cp.MatchPartial(prefix)
+}
+
+// Find searches s for the leftmost substring that matches the pattern and,
+// if found, obtains its start and end byte offsets. Of the matches that
+// begin at the leftmost position, the shortest is obtained, unless
+// LeftmostLongest is specified.
+func (cp CompiledPattern) Find(s string) (start, end int, ok bool) {
+
+	return cp.find(s, 0)
+}
+
+// FindAll obtains the start and end byte offsets of successive
+// non-overlapping matches of the pattern within s, as obtained by Find(),
+// up to a maximum of n matches, or of all matches if n is negative. An
+// empty match that abuts a preceding match is ignored.
+func (cp CompiledPattern) FindAll(s string, n int) [][]int {
+
+	var matches [][]int
+
+	prev_end := -1
+
+	for from := 0; n < 0 || len(matches) < n; {
+
+		start, end, ok := cp.find(s, from)
+
+		if !ok {
+
+			break
+		}
+
+		if start != end || end != prev_end {
+
+			matches = append(matches, []int{start, end})
+			prev_end = end
+		}
+
+		switch {
+
+		case start != end:
+
+			from = end
+		case len(s) == end:
+
+			return matches
+		default:
+
+			_, w := decode_char(s[end:])
+
+			from = end + w
+		}
+	}
+
+	return matches
+}
+
+// Contains determines whether any substring of s matches the pattern.
+func (cp CompiledPattern) Contains(s string) bool {
+
+	_, _, ok := cp.find(s, 0)
+
+	return ok
+}
+
 func (cp CompiledPattern) String() string {
 
 	switch cp.behaviour {
@@ -82,6 +380,17 @@ func (cp CompiledPattern) String() string {
 
 func Match(pattern string, s string, args ...any) (bool, error) {
 
+	// parse flags and options
+
+	o, err := parse_options_(args...)
+
+	if nil != err {
+
+		return false, err
+	}
+
+	flags := o.flags
+
 	// An empty pattern can only match an empty string
 
 	if 0 == len(pattern) {
@@ -89,9 +398,10 @@ func Match(pattern string, s string, args ...any) (bool, error) {
 		return 0 == len(s), nil
 	}
 
-	// A pattern composed entirely of '*' can match anything
+	// A pattern composed entirely of '*' can match anything, unless the
+	// stars cannot match the path separator or a leading period
 
-	allstar := true
+	allstar := 0 == ((PathMode | ExplicitLeadingPeriod) & flags)
 
 	for _, ch := range pattern {
 
@@ -107,11 +417,7 @@ func Match(pattern string, s string, args ...any) (bool, error) {
 		return true, nil
 	}
 
-	// parse flags
-
-	flags := parse_flags_(args...)
-
-	matchers, err := parse_matchers(pattern, flags)
+	matchers, err := parse_matchers(pattern, flags, o.separator)
 
 	if nil != err {
 
@@ -123,21 +429,33 @@ func Match(pattern string, s string, args ...any) (bool, error) {
 		panic("VIOLATION: empty matchers slice")
 	}
 
-	return match_from_compiled_(matchers, s)
+	return match_from_compiled_(compile_program(matchers, flags, o.separator), s)
 }
 
 func Compile(pattern string, args ...any) (CompiledPattern, error) {
 
+	// parse flags and options
+
+	o, err := parse_options_(args...)
+
+	if nil != err {
+
+		return CompiledPattern{}, err
+	}
+
+	flags := o.flags
+
 	// An empty pattern can only match an empty string
 
 	if 0 == len(pattern) {
 
-		return CompiledPattern{Pattern: pattern, matchers: nil, behaviour: _PB_EmptyPattern}, nil
+		return CompiledPattern{Pattern: pattern, flags: flags, separator: o.separator, prog: nil, behaviour: _PB_EmptyPattern}, nil
 	}
 
-	// A pattern composed entirely of '*' can match anything
+	// A pattern composed entirely of '*' can match anything, unless the
+	// stars cannot match the path separator or a leading period
 
-	allstar := true
+	allstar := 0 == ((PathMode | ExplicitLeadingPeriod) & flags)
 
 	for _, ch := range pattern {
 
@@ -150,14 +468,10 @@ func Compile(pattern string, args ...any) (CompiledPattern, error) {
 
 	if allstar {
 
-		return CompiledPattern{Pattern: pattern, matchers: nil, behaviour: _PB_AllWildPattern}, nil
+		return CompiledPattern{Pattern: pattern, flags: flags, separator: o.separator, prog: nil, behaviour: _PB_AllWildPattern}, nil
 	}
 
-	// parse flags
-
-	flags := parse_flags_(args...)
-
-	matchers, err := parse_matchers(pattern, flags)
+	matchers, err := parse_matchers(pattern, flags, o.separator)
 
 	if nil != err {
 
@@ -169,43 +483,44 @@ func Compile(pattern string, args ...any) (CompiledPattern, error) {
 		panic("VIOLATION: empty matchers slice")
 	}
 
-	return CompiledPattern{Pattern: pattern, matchers: matchers, behaviour: _PB_RegularPattern}, nil
+	return CompiledPattern{Pattern: pattern, flags: flags, separator: o.separator, prog: compile_program(matchers, flags, o.separator), behaviour: _PB_RegularPattern}, nil
 }
 
 /* /////////////////////////////////////////////////////////////////////////
  * internal functions
  */
 
-func parse_flags_(args ...any) uint64 {
+func (cp CompiledPattern) find(s string, from int) (start, end int, ok bool) {
 
-	var flags uint64 = 0
+	longest := 0 != (LeftmostLongest & cp.flags)
 
-	for i, arg := range args {
+	switch cp.behaviour {
 
-		switch v := arg.(type) {
+	case _PB_EmptyPattern:
 
-		case uint32:
+		return from, from, true
+	case _PB_AllWildPattern:
 
-			flags |= uint64(v)
+		if longest {
 
-		case uint64:
+			return from, len(s), true
+		}
 
-			flags |= v
+		return from, from, true
+	case _PB_RegularPattern:
 
-		default:
+		return cp.prog.search(s, from, longest)
+	default:
 
-			var msg = fmt.Sprintf("invalid type (%T) for argument '%v' at index %d", v, v, i)
+		msg := fmt.Sprintf("VIOLATION: unrecognised CompiledPattern.behaviour %d", cp.behaviour)
 
-			panic(msg)
-		}
+		panic(msg)
 	}
-
-	return 0
 }
 
-func match_from_compiled_(matchers []matcher, pattern string) (bool, error) {
+func match_from_compiled_(prog *program, s string) (bool, error) {
 
-	return matchers[0].match(pattern), nil
+	return prog.match(s), nil
 }
 
 /* ///////////////////////////// end of file //////////////////////////// */
@@ -0,0 +1,168 @@
+package shwild_test
+
+import (
+	shwild "github.com/synesissoftware/shwild.Go"
+
+	"fmt"
+	"strings"
+	"testing"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal functions
+ */
+
+func benchmark_CompiledPattern_Match(b *testing.B, pattern, s string) {
+
+	cp, err := shwild.Compile(pattern)
+	if err != nil {
+
+		b.Fatalf("Failed to compile pattern '%s'", pattern)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i != b.N; i++ {
+
+		cp.Match(s)
+	}
+}
+
+func make_extension_patterns(n int) []string {
+
+	patterns := make([]string, n)
+
+	for ix := range patterns {
+
+		patterns[ix] = fmt.Sprintf("*.x%d", ix)
+	}
+
+	return append(patterns, "*.go")
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * benchmarks
+ */
+
+func Benchmark_CompiledPattern_Match_literal(b *testing.B) {
+
+	benchmark_CompiledPattern_Match(b, "README.md", "README.md")
+}
+
+func Benchmark_CompiledPattern_Match_extension(b *testing.B) {
+
+	benchmark_CompiledPattern_Match(b, "*.go", "compiled_pattern_test.go")
+}
+
+func Benchmark_CompiledPattern_Match_range_and_wildcards(b *testing.B) {
+
+	benchmark_CompiledPattern_Match(b, "[ER]*.m?", "EXAMPLES.md")
+}
+
+func Benchmark_CompiledPattern_Match_wide_range(b *testing.B) {
+
+	benchmark_CompiledPattern_Match(b, "[\u0000-\U0010ffff]*[\u4e00-\u9fff]", "file-\u6f22\u5b57")
+}
+
+func Benchmark_Compile_wide_range(b *testing.B) {
+
+	for i := 0; i != b.N; i++ {
+
+		shwild.Compile("[\u0000-\U0010ffff]")
+	}
+}
+
+func Benchmark_CompiledPattern_Match_adversarial_40(b *testing.B) {
+
+	benchmark_CompiledPattern_Match(b, "*a*a*a*a*a*b", strings.Repeat("a", 40))
+}
+
+func Benchmark_CompiledPattern_Match_adversarial_1000(b *testing.B) {
+
+	benchmark_CompiledPattern_Match(b, "*a*a*a*a*a*b", strings.Repeat("a", 1000))
+}
+
+func Benchmark_CompiledPattern_Match_adversarial_10000(b *testing.B) {
+
+	benchmark_CompiledPattern_Match(b, "*a*a*a*a*a*b", strings.Repeat("a", 10000))
+}
+
+func Benchmark_CompiledPattern_FindSubmatch_adversarial_400(b *testing.B) {
+
+	pattern := strings.Repeat("?*", 400) + "a"
+	s := strings.Repeat("b", 800) + "a"
+
+	cp, err := shwild.Compile(pattern)
+	if err != nil {
+
+		b.Fatalf("Failed to compile pattern '%s'", pattern)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i != b.N; i++ {
+
+		cp.FindSubmatch(s)
+	}
+}
+
+func Benchmark_CompiledPattern_MatchBytes_extension(b *testing.B) {
+
+	cp, err := shwild.Compile("*.go")
+	if err != nil {
+
+		b.Fatalf("Failed to compile pattern '%s'", "*.go")
+	}
+
+	s := []byte("compiled_pattern_test.go")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i != b.N; i++ {
+
+		cp.MatchBytes(s)
+	}
+}
+
+func Benchmark_CompiledPattern_Match_1000_patterns(b *testing.B) {
+
+	var cps []shwild.CompiledPattern
+
+	for _, pattern := range make_extension_patterns(1000) {
+
+		cp, _ := shwild.Compile(pattern)
+
+		cps = append(cps, cp)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i != b.N; i++ {
+
+		for _, cp := range cps {
+
+			cp.Match("compiled_pattern_test.go")
+		}
+	}
+}
+
+func Benchmark_PatternSet_Match_1000_patterns(b *testing.B) {
+
+	ps, err := shwild.CompileSet(make_extension_patterns(1000))
+	if err != nil {
+
+		b.Fatalf("Failed to compile set: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i != b.N; i++ {
+
+		ps.MatchFirst("compiled_pattern_test.go")
+	}
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
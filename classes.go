@@ -0,0 +1,73 @@
+// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2026
+// Matthew Wilson and Synesis Information Systems. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+/*
+ * Created: 18th October 2026
+ * Updated: 18th October 2026
+ */
+
+package shwild
+
+import (
+	"unicode"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal types
+ */
+
+// char_class structure
+//
+// A POSIX character class, as in [[:alpha:]], whose membership is
+// determined according to the Unicode general categories, following the
+// POSIX-compatible definitions of Unicode Technical Standard #18, Annex C.
+
+type char_class struct {
+	name     string
+	contains func(r rune) bool
+}
+
+func (cc char_class) String() string {
+
+	return "[:" + cc.name + ":]"
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal variables
+ */
+
+var char_classes = []char_class{
+	{"alnum", func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }},
+	{"alpha", unicode.IsLetter},
+	{"blank", func(r rune) bool { return '\t' == r || unicode.Is(unicode.Zs, r) }},
+	{"cntrl", unicode.IsControl},
+	{"digit", unicode.IsDigit},
+	{"graph", func(r rune) bool { return unicode.IsGraphic(r) && !unicode.IsSpace(r) }},
+	{"lower", unicode.IsLower},
+	{"print", func(r rune) bool { return unicode.IsGraphic(r) && !unicode.IsControl(r) }},
+	{"punct", func(r rune) bool { return unicode.IsPunct(r) || unicode.IsSymbol(r) }},
+	{"space", unicode.IsSpace},
+	{"upper", unicode.IsUpper},
+	{"xdigit", func(r rune) bool { return ('0' <= r && r <= '9') || ('a' <= r && r <= 'f') || ('A' <= r && r <= 'F') }},
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal functions
+ */
+
+func lookup_char_class(name string) (char_class, bool) {
+
+	for _, cc := range char_classes {
+
+		if name == cc.name {
+
+			return cc, true
+		}
+	}
+
+	return char_class{}, false
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
@@ -3,10 +3,16 @@ package shwild_test
 import (
 	shwild "github.com/synesissoftware/shwild.Go"
 
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"path"
 	"runtime"
+	"slices"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 /* /////////////////////////////////////////////////////////////////////////
@@ -503,4 +509,754 @@ func Test_CompiledPattern_Match_from_examples_1(t *testing.T) {
 	check_CompiledPattern_Match(t, cp, "LICENSE", false, nil)
 }
 
+func Test_CompiledPattern_Match_with_multibyte_characters(t *testing.T) {
+
+	pattern := "r[\u00e9e]sum?.*"
+
+	cp, err := shwild.Compile(pattern)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "r\u00e9sum\u00e9.pdf", true, nil)
+	check_CompiledPattern_Match(t, cp, "resume.pdf", true, nil)
+	check_CompiledPattern_Match(t, cp, "r\u00e8sum\u00e9.pdf", false, nil)
+	check_CompiledPattern_Match(t, cp, "r\xffsum\u00e9.pdf", false, nil)
+
+	pattern = "[^\u00e9]?"
+
+	cp, err = shwild.Compile(pattern)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "\u00e8\u00e9", true, nil)
+	check_CompiledPattern_Match(t, cp, "\u00e9\u00e8", false, nil)
+	check_CompiledPattern_Match(t, cp, "\xff\xfe", true, nil)
+}
+
+func Test_CompiledPattern_Match_with_adversarial_patterns(t *testing.T) {
+
+	// each of these would take exponential time with a backtracking
+	// matcher
+
+	pattern := "*a*a*a*a*a*a*a*a*b"
+
+	cp, err := shwild.Compile(pattern)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, strings.Repeat("a", 10000), false, nil)
+	check_CompiledPattern_Match(t, cp, strings.Repeat("a", 10000)+"b", true, nil)
+
+	pattern = strings.Repeat("?*", 500) + "!"
+
+	cp, err = shwild.Compile(pattern)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, strings.Repeat("x", 1000), false, nil)
+	check_CompiledPattern_Match(t, cp, strings.Repeat("x", 1000)+"!", true, nil)
+	check_CompiledPattern_Match(t, cp, strings.Repeat("x", 499)+"!", false, nil)
+
+	// a very long chain, which would exhaust the stack of a recursive
+	// matcher
+
+	pattern = strings.Repeat("a?", 100000)
+
+	cp, err = shwild.Compile(pattern)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, strings.Repeat("ab", 100000), true, nil)
+	check_CompiledPattern_Match(t, cp, strings.Repeat("ab", 99999), false, nil)
+}
+
+func Test_CompiledPattern_Match_with_AllowRangeQuantification(t *testing.T) {
+
+	pattern := "build-[0-9]\\4.tar.gz"
+
+	cp, err := shwild.Compile(pattern, shwild.AllowRangeQuantification)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "build-0042.tar.gz", true, nil)
+	check_CompiledPattern_Match(t, cp, "build-042.tar.gz", false, nil)
+	check_CompiledPattern_Match(t, cp, "build-00042.tar.gz", false, nil)
+	check_CompiledPattern_Match(t, cp, "build-004x.tar.gz", false, nil)
+
+	pattern = "*.[a-z]\\1-4"
+
+	cp, err = shwild.Compile(pattern, shwild.AllowRangeQuantification)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "archive.tar.gz", true, nil)
+	check_CompiledPattern_Match(t, cp, "index.html", true, nil)
+	check_CompiledPattern_Match(t, cp, "index.xhtml", false, nil)
+	check_CompiledPattern_Match(t, cp, "README.", false, nil)
+}
+
+func Test_CompiledPattern_Match_with_AllowRangeLiteralBracket(t *testing.T) {
+
+	pattern := "server[[]*[]].log"
+
+	cp, err := shwild.Compile(pattern, shwild.AllowRangeLiteralBracket)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "server[eu-west].log", true, nil)
+	check_CompiledPattern_Match(t, cp, "server.log", false, nil)
+
+	// without the flag, [ and a leading ] are still literal
+
+	cp, err = shwild.Compile(pattern)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "server[eu-west].log", true, nil)
+
+	// but a ] that precedes the closing ] is literal only with the flag
+
+	pattern = "server[[]*[0-9]].log"
+
+	cp, err = shwild.Compile(pattern, shwild.AllowRangeLiteralBracket)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "server[eu-west].log", true, nil)
+
+	cp, err = shwild.Compile(pattern)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "server[eu-west].log", false, nil)
+	check_CompiledPattern_Match(t, cp, "server[eu-west2].log", true, nil)
+}
+
+func Test_Compile_with_invalid_patterns(t *testing.T) {
+
+	for _, tc := range []struct {
+		pattern string
+		kind    shwild.PatternErrorKind
+		offset  int
+	}{
+		{"[abc", shwild.UnterminatedRange, 0},
+		{"*.[ch", shwild.UnterminatedRange, 2},
+		{"[]", shwild.EmptyRange, 0},
+		{"*.c\\", shwild.TrailingEscape, 3},
+		{"[0-3-9]", shwild.InvalidContinuum, 4},
+		{"[[:alhpa:]]", shwild.InvalidClass, 1},
+		{"[a-[:digit:]]", shwild.InvalidContinuum, 2},
+	} {
+
+		cp, err := shwild.Compile(tc.pattern)
+
+		var pe *shwild.PatternError
+
+		if !errors.As(err, &pe) {
+
+			t.Errorf("Compile('%s') returned %v, %v; *PatternError expected", tc.pattern, cp, err)
+
+			continue
+		}
+
+		if tc.kind != pe.Kind || tc.offset != pe.Offset {
+
+			t.Errorf("Compile('%s') failed with %v at offset %d; %v at offset %d expected", tc.pattern, pe.Kind, pe.Offset, tc.kind, tc.offset)
+		}
+	}
+}
+
+func Test_CompiledPattern_Match_with_SuppressRangeSupport(t *testing.T) {
+
+	pattern := "[abc]"
+
+	cp, err := shwild.Compile(pattern, shwild.SuppressRangeSupport)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "a", false, nil)
+	check_CompiledPattern_Match(t, cp, "[abc]", true, nil)
+}
+
+func Test_CompiledPattern_Match_with_SuppressBackslashEscape(t *testing.T) {
+
+	pattern := "a\\*"
+
+	cp, err := shwild.Compile(pattern, shwild.SuppressBackslashEscape)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "a*", false, nil)
+	check_CompiledPattern_Match(t, cp, "a\\", true, nil)
+	check_CompiledPattern_Match(t, cp, "a\\bc", true, nil)
+}
+
+func Test_CompiledPattern_Match_with_SuppressRangeContinuumSupport(t *testing.T) {
+
+	pattern := "[a-c]"
+
+	cp, err := shwild.Compile(pattern, shwild.SuppressRangeContinuumSupport)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "a", true, nil)
+	check_CompiledPattern_Match(t, cp, "b", false, nil)
+	check_CompiledPattern_Match(t, cp, "-", true, nil)
+}
+
+func Test_CompiledPattern_Match_with_SuppressRangeContinuumHighlowSupport(t *testing.T) {
+
+	pattern := "[c-a]"
+
+	cp, err := shwild.Compile(pattern, shwild.SuppressRangeContinuumHighlowSupport)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "a", true, nil)
+	check_CompiledPattern_Match(t, cp, "b", false, nil)
+	check_CompiledPattern_Match(t, cp, "-", true, nil)
+}
+
+func Test_CompiledPattern_Match_with_SuppressRangeContinuumCrosscaseSupport(t *testing.T) {
+
+	pattern := "[a-C]"
+
+	cp, err := shwild.Compile(pattern, shwild.SuppressRangeContinuumCrosscaseSupport)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "b", false, nil)
+	check_CompiledPattern_Match(t, cp, "B", false, nil)
+	check_CompiledPattern_Match(t, cp, "Z", true, nil)
+}
+
+func Test_CompiledPattern_Match_with_SuppressRangeLiteralWildcard(t *testing.T) {
+
+	pattern := "[*?]"
+
+	_, err := shwild.Compile(pattern, shwild.SuppressRangeLiteralWildcard)
+	if err == nil {
+
+		t.Errorf("Compiled invalid pattern '%s'", pattern)
+	}
+}
+
+func Test_CompiledPattern_Match_with_SuppressRangeLeadtrailLiteralHyphen(t *testing.T) {
+
+	pattern := "[a-]"
+
+	_, err := shwild.Compile(pattern, shwild.SuppressRangeLeadtrailLiteralHyphen)
+	if err == nil {
+
+		t.Errorf("Compiled invalid pattern '%s'", pattern)
+	}
+}
+
+func Test_CompiledPattern_Match_with_SuppressRangeNot(t *testing.T) {
+
+	pattern := "[^a]"
+
+	cp, err := shwild.Compile(pattern, shwild.SuppressRangeNot)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "a", true, nil)
+	check_CompiledPattern_Match(t, cp, "^", true, nil)
+	check_CompiledPattern_Match(t, cp, "b", false, nil)
+}
+
+func Test_CompiledPattern_Match_with_IgnoreCase(t *testing.T) {
+
+	pattern := "*.JPG"
+
+	cp, err := shwild.Compile(pattern, shwild.IgnoreCase)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "photo.jpg", true, nil)
+	check_CompiledPattern_Match(t, cp, "photo.Jpg", true, nil)
+	check_CompiledPattern_Match(t, cp, "photo.png", false, nil)
+
+	pattern = "[^a-c]?"
+
+	cp, err = shwild.Compile(pattern, shwild.IgnoreCase)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "Bx", false, nil)
+	check_CompiledPattern_Match(t, cp, "Dx", true, nil)
+
+	pattern = "\u03a3*\u00df"
+
+	cp, err = shwild.Compile(pattern, shwild.IgnoreCase)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "\u03c3-\u1e9e", true, nil)
+	check_CompiledPattern_Match(t, cp, "\u03c2-\u00df", true, nil)
+	check_CompiledPattern_Match(t, cp, "s-\u00df", false, nil)
+}
+
+func Test_CompiledPattern_Match_with_AllowBraceAlternation(t *testing.T) {
+
+	pattern := "{src,test}/*.{go,s}"
+
+	cp, err := shwild.Compile(pattern, shwild.AllowBraceAlternation)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "src/main.go", true, nil)
+	check_CompiledPattern_Match(t, cp, "test/asm.s", true, nil)
+	check_CompiledPattern_Match(t, cp, "doc/main.go", false, nil)
+	check_CompiledPattern_Match(t, cp, "src/main.c", false, nil)
+}
+
+func Test_CompiledPattern_Match_with_PathMode(t *testing.T) {
+
+	pattern := "src/**/*_test.go"
+
+	cp, err := shwild.Compile(pattern, shwild.PathMode)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "src/a_test.go", true, nil)
+	check_CompiledPattern_Match(t, cp, "src/a/b/c_test.go", true, nil)
+	check_CompiledPattern_Match(t, cp, "src/a/b/c.go", false, nil)
+	check_CompiledPattern_Match(t, cp, "lib/src/a_test.go", false, nil)
+
+	pattern = "*"
+
+	cp, err = shwild.Compile(pattern, shwild.PathMode)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "main.go", true, nil)
+	check_CompiledPattern_Match(t, cp, "src/main.go", false, nil)
+}
+
+func Test_CompiledPattern_Match_with_ExplicitLeadingPeriod(t *testing.T) {
+
+	pattern := "*"
+
+	cp, err := shwild.Compile(pattern, shwild.ExplicitLeadingPeriod)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "bashrc", true, nil)
+	check_CompiledPattern_Match(t, cp, ".bashrc", false, nil)
+
+	pattern = "src/*/*.go"
+
+	cp, err = shwild.Compile(pattern, shwild.ExplicitLeadingPeriod, shwild.PathMode)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "src/a/main.go", true, nil)
+	check_CompiledPattern_Match(t, cp, "src/.a/main.go", false, nil)
+	check_CompiledPattern_Match(t, cp, "src/a/.go", false, nil)
+}
+
+func Test_CompiledPattern_Match_with_character_classes(t *testing.T) {
+
+	pattern := "[[:upper:]][[:lower:]]*[[:digit:]_]"
+
+	cp, err := shwild.Compile(pattern)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "Name1", true, nil)
+	check_CompiledPattern_Match(t, cp, "\u00c9t\u00e9_", true, nil)
+	check_CompiledPattern_Match(t, cp, "name1", false, nil)
+	check_CompiledPattern_Match(t, cp, "Name", false, nil)
+
+	pattern = "[^[:space:]]*"
+
+	cp, err = shwild.Compile(pattern)
+	if err != nil {
+
+		t.Errorf("Failed to compile pattern '%s'", pattern)
+	}
+
+	check_CompiledPattern_Match(t, cp, "word", true, nil)
+	check_CompiledPattern_Match(t, cp, " word", false, nil)
+	check_CompiledPattern_Match(t, cp, "\u3000word", false, nil)
+}
+
+func Test_CompiledPattern_FindSubmatch(t *testing.T) {
+
+	for _, tc := range []struct {
+		pattern  string
+		flags    int
+		s        string
+		expected []string
+	}{
+		{"*.jpeg", 0, "photo.jpeg", []string{"photo.jpeg", "photo"}},
+		{"*.jpeg", 0, "photo.jpg", nil},
+		{"img_???.*", 0, "img_042.png", []string{"img_042.png", "0", "4", "2", "png"}},
+		{"[A-Z]*[0-9]", 0, "File7", []string{"File7", "F", "ile", "7"}},
+		{"[^.]*.txt", 0, "readme.txt", []string{"readme.txt", "r", "eadme"}},
+		{"*-*", 0, "a-b-c", []string{"a-b-c", "a-b", "c"}},
+		{"abc", 0, "abc", []string{"abc"}},
+		{"", 0, "", []string{""}},
+		{"*", 0, "abc", []string{"abc", "abc"}},
+		{"**", 0, "abc", []string{"abc", "abc", ""}},
+		{"é*é", 0, "éèé", []string{"éèé", "è"}},
+		{"[0-9]\\4[-]*", shwild.AllowRangeQuantification, "2024-10", []string{"2024-10", "2024", "-", "10"}},
+		{"*.{jpg,[pP]ng}", shwild.AllowBraceAlternation, "a.jpg", []string{"a.jpg", "a", ""}},
+		{"*.{jpg,[pP]ng}", shwild.AllowBraceAlternation, "a.Png", []string{"a.Png", "a", "P"}},
+		{"src/**/*.go", shwild.PathMode, "src/a/b/main.go", []string{"src/a/b/main.go", "a/b/", "main"}},
+		{"src/**/*.go", shwild.PathMode, "src/main.go", []string{"src/main.go", "", "main"}},
+		{"src/**", shwild.PathMode, "src/a/b", []string{"src/a/b", "/a/b"}},
+		{"*/*", shwild.PathMode, "a/b", []string{"a/b", "a", "b"}},
+	} {
+
+		cp, err := shwild.Compile(tc.pattern, tc.flags)
+		if err != nil {
+
+			t.Errorf("Failed to compile pattern '%s'", tc.pattern)
+
+			continue
+		}
+
+		submatches, matched := cp.FindSubmatch(tc.s)
+
+		if (nil != tc.expected) != matched || !slices.Equal(tc.expected, submatches) {
+
+			t.Errorf("FindSubmatch('%s') against pattern '%s' returned %q, %v; %q expected", tc.s, tc.pattern, submatches, matched, tc.expected)
+		}
+
+		if m, _ := cp.Match(tc.s); m != matched {
+
+			t.Errorf("FindSubmatch('%s') against pattern '%s' returned %v; Match() returned %v", tc.s, tc.pattern, matched, m)
+		}
+	}
+}
+
+func Test_CompiledPattern_FindSubmatchIndex(t *testing.T) {
+
+	pattern := "*.{jpg,[pP]ng}"
+
+	cp, err := shwild.Compile(pattern, shwild.AllowBraceAlternation)
+	if err != nil {
+
+		t.Fatalf("Failed to compile pattern '%s'", pattern)
+	}
+
+	indexes, matched := cp.FindSubmatchIndex("a.jpg")
+
+	if expected := []int{0, 5, 0, 1, -1, -1}; !matched || !slices.Equal(expected, indexes) {
+
+		t.Errorf("FindSubmatchIndex() returned %v, %v; %v expected", indexes, matched, expected)
+	}
+
+	indexes, matched = cp.FindSubmatchIndex("ab.png")
+
+	if expected := []int{0, 6, 0, 2, 3, 4}; !matched || !slices.Equal(expected, indexes) {
+
+		t.Errorf("FindSubmatchIndex() returned %v, %v; %v expected", indexes, matched, expected)
+	}
+
+	if indexes, matched = cp.FindSubmatchIndex("a.gif"); matched || nil != indexes {
+
+		t.Errorf("FindSubmatchIndex() returned %v, %v; nil, false expected", indexes, matched)
+	}
+}
+
+func Test_CompiledPattern_Find(t *testing.T) {
+
+	for _, tc := range []struct {
+		pattern string
+		flags   int
+		s       string
+		start   int
+		end     int
+		ok      bool
+	}{
+		{"err*", 0, "an error occurred", 3, 6, true},
+		{"err*", shwild.LeftmostLongest, "an error occurred", 3, 17, true},
+		{"err??", 0, "an error occurred", 3, 8, true},
+		{"[0-9]", 0, "code 42", 5, 6, true},
+		{"[0-9]*[0-9]", 0, "code 4242", 5, 7, true},
+		{"[0-9]*[0-9]", shwild.LeftmostLongest, "code 4242", 5, 9, true},
+		{"a*b", 0, "xaxaxb", 1, 6, true},
+		{"b*", 0, "abc", 1, 2, true},
+		{"xyz", 0, "abc", 0, 0, false},
+		{"abc", 0, "abc", 0, 3, true},
+		{"*", 0, "abc", 0, 0, true},
+		{"*", shwild.LeftmostLongest, "abc", 0, 3, true},
+		{"", 0, "abc", 0, 0, true},
+		{"é*é", 0, "xéyézé", 1, 6, true},
+		{"é*é", shwild.LeftmostLongest, "xéyézé", 1, 9, true},
+		{"{cat,dog}", shwild.AllowBraceAlternation, "hotdog", 3, 6, true},
+		{"*.go", shwild.PathMode, "see src/main.go", 8, 15, true},
+		{"ERR*", shwild.IgnoreCase, "an error", 3, 6, true},
+	} {
+
+		cp, err := shwild.Compile(tc.pattern, tc.flags)
+		if err != nil {
+
+			t.Errorf("Failed to compile pattern '%s'", tc.pattern)
+
+			continue
+		}
+
+		start, end, ok := cp.Find(tc.s)
+
+		if tc.ok != ok || (ok && (tc.start != start || tc.end != end)) {
+
+			t.Errorf("Find('%s') against pattern '%s' returned %d, %d, %v; %d, %d, %v expected", tc.s, tc.pattern, start, end, ok, tc.start, tc.end, tc.ok)
+		}
+
+		if contains := cp.Contains(tc.s); tc.ok != contains {
+
+			t.Errorf("Contains('%s') against pattern '%s' returned %v; %v expected", tc.s, tc.pattern, contains, tc.ok)
+		}
+	}
+}
+
+func Test_CompiledPattern_FindAll(t *testing.T) {
+
+	for _, tc := range []struct {
+		pattern  string
+		flags    int
+		s        string
+		n        int
+		expected [][]int
+	}{
+		{"[0-9]", 0, "a1b22c333", -1, [][]int{{1, 2}, {3, 4}, {4, 5}, {6, 7}, {7, 8}, {8, 9}}},
+		{"[0-9]", 0, "a1b22c333", 2, [][]int{{1, 2}, {3, 4}}},
+		{"[0-9]", 0, "a1b22c333", 0, nil},
+		{"[0-9]\\1-", shwild.AllowRangeQuantification | shwild.LeftmostLongest, "a1b22c333", -1, [][]int{{1, 2}, {3, 5}, {6, 9}}},
+		{"[0-9]\\1-", shwild.AllowRangeQuantification, "a1b22c333", -1, [][]int{{1, 2}, {3, 4}, {4, 5}, {6, 7}, {7, 8}, {8, 9}}},
+		{"<*>", 0, "<a> and <b>", -1, [][]int{{0, 3}, {8, 11}}},
+		{"<*>", shwild.LeftmostLongest, "<a> and <b>", -1, [][]int{{0, 11}}},
+		{"xyz", 0, "abc", -1, nil},
+		{"*", 0, "ab", -1, [][]int{{0, 0}, {1, 1}, {2, 2}}},
+		{"*", shwild.LeftmostLongest, "ab", -1, [][]int{{0, 2}}},
+		{"a*", shwild.LeftmostLongest, "xa", -1, [][]int{{1, 2}}},
+		{"?", 0, "éè", -1, [][]int{{0, 2}, {2, 4}}},
+	} {
+
+		cp, err := shwild.Compile(tc.pattern, tc.flags)
+		if err != nil {
+
+			t.Errorf("Failed to compile pattern '%s'", tc.pattern)
+
+			continue
+		}
+
+		matches := cp.FindAll(tc.s, tc.n)
+
+		if !slices.EqualFunc(tc.expected, matches, slices.Equal[[]int]) {
+
+			t.Errorf("FindAll('%s', %d) against pattern '%s' returned %v; %v expected", tc.s, tc.n, tc.pattern, matches, tc.expected)
+		}
+	}
+}
+
+func Test_CompiledPattern_MatchPartial(t *testing.T) {
+
+	for _, tc := range []struct {
+		pattern  string
+		flags    int
+		prefix   string
+		expected shwild.PartialResult
+	}{
+		{"src/*.go", 0, "", shwild.NeedMore},
+		{"src/*.go", 0, "sr", shwild.NeedMore},
+		{"src/*.go", 0, "src/main.go", shwild.Matched},
+		{"src/*.go", 0, "src/main.go.bak", shwild.NeedMore},
+		{"src/*.go", 0, "test/", shwild.NoMatch},
+		{"src/*.go", shwild.PathMode, "src/a/", shwild.NoMatch},
+		{"src/**/*.go", shwild.PathMode, "src/a/b/", shwild.NeedMore},
+		{"src/**/*.go", shwild.PathMode, "test/", shwild.NoMatch},
+		{"[0-9]\\4", shwild.AllowRangeQuantification, "20", shwild.NeedMore},
+		{"[0-9]\\4", shwild.AllowRangeQuantification, "2024", shwild.Matched},
+		{"[0-9]\\4", shwild.AllowRangeQuantification, "20245", shwild.NoMatch},
+		{"a[/]", shwild.PathMode, "a", shwild.NoMatch},
+		{"abc", 0, "abc", shwild.Matched},
+		{"abc", 0, "abcd", shwild.NoMatch},
+		{"", 0, "", shwild.Matched},
+		{"", 0, "a", shwild.NoMatch},
+		{"*", 0, "a", shwild.Matched},
+	} {
+
+		cp, err := shwild.Compile(tc.pattern, tc.flags)
+		if err != nil {
+
+			t.Errorf("Failed to compile pattern '%s'", tc.pattern)
+
+			continue
+		}
+
+		if actual := cp.MatchPartial(tc.prefix); tc.expected != actual {
+
+			t.Errorf("MatchPartial('%s') against pattern '%s' returned %v; %v expected", tc.prefix, tc.pattern, actual, tc.expected)
+		}
+
+		if possible := cp.MatchPrefix(tc.prefix); (shwild.NoMatch != tc.expected) != possible {
+
+			t.Errorf("MatchPrefix('%s') against pattern '%s' returned %v", tc.prefix, tc.pattern, possible)
+		}
+	}
+}
+
+func Test_CompiledPattern_MatchBytes_MatchRunes_and_MatchReader(t *testing.T) {
+
+	for _, tc := range []struct {
+		pattern string
+		flags   int
+	}{
+		{"", 0},
+		{"*", 0},
+		{"abc", 0},
+		{"a?c", 0},
+		{"*.go", 0},
+		{"[a-c]*[^x]", 0},
+		{"*é?", 0},
+		{"ABC", shwild.IgnoreCase},
+		{"{*.go,*.md}", shwild.AllowBraceAlternation},
+		{"src/**/*.go", shwild.PathMode},
+		{"*/*", shwild.PathMode | shwild.ExplicitLeadingPeriod},
+		{"[0-9]\\4", shwild.AllowRangeQuantification},
+	} {
+
+		cp, err := shwild.Compile(tc.pattern, tc.flags)
+		if err != nil {
+
+			t.Errorf("Failed to compile pattern '%s'", tc.pattern)
+
+			continue
+		}
+
+		for _, s := range []string{
+			"",
+			"a",
+			"abc",
+			"aBc",
+			"abx",
+			"main.go",
+			".go",
+			"README.md",
+			"café!",
+			"src/main.go",
+			"src/a/b/main.go",
+			"a/.b",
+			"a/b",
+			"2024",
+		} {
+
+			expected, _ := cp.Match(s)
+
+			if actual, err := cp.MatchBytes([]byte(s)); expected != actual || nil != err {
+
+				t.Errorf("MatchBytes('%s') against pattern '%s' returned (%v, %v); %v expected", s, tc.pattern, actual, err, expected)
+			}
+
+			if actual, err := cp.MatchRunes([]rune(s)); expected != actual || nil != err {
+
+				t.Errorf("MatchRunes('%s') against pattern '%s' returned (%v, %v); %v expected", s, tc.pattern, actual, err, expected)
+			}
+
+			if actual, err := cp.MatchReader(strings.NewReader(s)); expected != actual || nil != err {
+
+				t.Errorf("MatchReader('%s') against pattern '%s' returned (%v, %v); %v expected", s, tc.pattern, actual, err, expected)
+			}
+		}
+	}
+}
+
+func Test_CompiledPattern_MatchBytes_with_invalid_UTF8(t *testing.T) {
+
+	cp, _ := shwild.Compile("a?\xff")
+
+	if matched, _ := cp.MatchBytes([]byte("a\xfe\xff")); !matched {
+
+		t.Errorf("MatchBytes() with invalid UTF-8 failed to match")
+	}
+}
+
+func Test_CompiledPattern_MatchReader_stops_reading(t *testing.T) {
+
+	cp, _ := shwild.Compile("ab*")
+
+	r := strings.NewReader("axxxxxxxx")
+
+	if matched, err := cp.MatchReader(r); matched || nil != err {
+
+		t.Errorf("MatchReader() returned (%v, %v); (false, nil) expected", matched, err)
+	}
+
+	if expected, actual := 7, r.Len(); expected != actual {
+
+		t.Errorf("MatchReader() left %d unread bytes; %d expected", actual, expected)
+	}
+}
+
+func Test_CompiledPattern_MatchReader_with_read_error(t *testing.T) {
+
+	cp, _ := shwild.Compile("a*")
+
+	read_err := errors.New("read failed")
+
+	r := bufio.NewReader(io.MultiReader(strings.NewReader("abc"), iotest.ErrReader(read_err)))
+
+	if matched, err := cp.MatchReader(r); matched || read_err != err {
+
+		t.Errorf("MatchReader() returned (%v, %v); (false, %v) expected", matched, err, read_err)
+	}
+}
+
 /* ///////////////////////////// end of file //////////////////////////// */
@@ -0,0 +1,278 @@
+// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2026
+// Matthew Wilson and Synesis Information Systems. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+/*
+ * Created: 18th October 2026
+ * Updated: 18th October 2026
+ */
+
+package shwild
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * API types
+ */
+
+// PatternErrorKind indicates the nature of a pattern parsing failure.
+//
+// PatternErrorKind implements error, and is returned from
+// PatternError.Unwrap(), so that the kind of failure may be tested
+// directly with errors.Is(), as in:
+//
+//	if errors.Is(err, shwild.UnterminatedRange) {
+//		. . .
+//	}
+type PatternErrorKind int
+
+const (
+	// A range is not closed by ], as in "[abc"
+	UnterminatedRange PatternErrorKind = 1 + iota
+
+	// A range contains no characters, as in "[]"
+	EmptyRange
+
+	// The pattern ends with an escape character, as in "abc\"
+	TrailingEscape
+
+	// A range contains an ill-formed continuum, as in "[a-c-e]"
+	InvalidContinuum
+
+	// A range contains ? or *, and SuppressRangeLiteralWildcard is
+	// specified
+	WildcardInRange
+
+	// A range contains a leading or trailing hyphen, and
+	// SuppressRangeLeadtrailLiteralHyphen is specified
+	LeadtrailHyphenInRange
+
+	// A quantifier is malformed, or does not follow a literal character, ?
+	// or a range, and AllowRangeQuantification is specified
+	InvalidQuantifier
+
+	// A range contains an unknown or unterminated character class, as in
+	// "[[:alhpa:]]"
+	InvalidClass
+
+	// The pattern - or the patterns of a set, together - would compile to
+	// a program larger than is permitted, as may be the case when it has
+	// many large quantifiers
+	PatternTooLarge
+)
+
+func (k PatternErrorKind) String() string {
+
+	switch k {
+
+	case UnterminatedRange:
+		return "unterminated range"
+	case EmptyRange:
+		return "empty range"
+	case TrailingEscape:
+		return "trailing escape"
+	case InvalidContinuum:
+		return "invalid continuum"
+	case WildcardInRange:
+		return "wildcard in range"
+	case LeadtrailHyphenInRange:
+		return "leading or trailing hyphen in range"
+	case InvalidQuantifier:
+		return "invalid quantifier"
+	case InvalidClass:
+		return "invalid character class"
+	case PatternTooLarge:
+		return "pattern too large"
+	}
+
+	return fmt.Sprintf("<%T %d>", k, k)
+}
+
+func (k PatternErrorKind) Error() string {
+
+	return k.String()
+}
+
+// PatternError describes a failure to parse a pattern, including the
+// position within the pattern at which the failure was detected.
+type PatternError struct {
+	Pattern    string           // The pattern
+	Offset     int              // The byte offset of the offending character
+	RuneOffset int              // The rune offset of the offending character
+	Kind       PatternErrorKind // The nature of the failure
+}
+
+func (e *PatternError) Error() string {
+
+	return fmt.Sprintf("invalid pattern '%s': %v at offset %d", e.Pattern, e.Kind, e.RuneOffset)
+}
+
+func (e *PatternError) Unwrap() error {
+
+	return e.Kind
+}
+
+// TemplateErrorKind indicates the nature of a replacement template parsing
+// failure.
+//
+// As with PatternErrorKind, TemplateErrorKind implements error, and is
+// returned from TemplateError.Unwrap().
+type TemplateErrorKind int
+
+const (
+	// A reference is not closed by }, as in "${1"
+	UnterminatedReference TemplateErrorKind = 1 + iota
+
+	// A reference is not a number, as in "${a}"
+	InvalidReference
+
+	// A reference refers to a wildcard that is not in the pattern, as in
+	// "#2" for the pattern "*.txt"
+	ReferenceOutOfRange
+)
+
+func (k TemplateErrorKind) String() string {
+
+	switch k {
+
+	case UnterminatedReference:
+		return "unterminated reference"
+	case InvalidReference:
+		return "invalid reference"
+	case ReferenceOutOfRange:
+		return "reference out of range"
+	}
+
+	return fmt.Sprintf("<%T %d>", k, k)
+}
+
+func (k TemplateErrorKind) Error() string {
+
+	return k.String()
+}
+
+// TemplateError describes a failure to parse a replacement template,
+// including the position within the template at which the failure was
+// detected.
+type TemplateError struct {
+	Template   string            // The template
+	Offset     int               // The byte offset of the offending reference
+	RuneOffset int               // The rune offset of the offending reference
+	Kind       TemplateErrorKind // The nature of the failure
+}
+
+func (e *TemplateError) Error() string {
+
+	return fmt.Sprintf("invalid template '%s': %v at offset %d", e.Template, e.Kind, e.RuneOffset)
+}
+
+func (e *TemplateError) Unwrap() error {
+
+	return e.Kind
+}
+
+// CollisionError describes a renaming that cannot be performed because its
+// destination would be shared by more than one source, or is already
+// occupied by a name that is not itself renamed.
+type CollisionError struct {
+	To     string   // The destination
+	From   []string // The sources that would be renamed to To
+	Exists bool     // Indicates whether To is already occupied
+}
+
+func (e *CollisionError) Error() string {
+
+	if e.Exists {
+
+		return fmt.Sprintf("cannot rename %q to %q: destination exists", e.From, e.To)
+	}
+
+	return fmt.Sprintf("cannot rename %q to %q: destination shared", e.From, e.To)
+}
+
+// RuleError describes a failure to parse a line of a rule list, including
+// the number of the line.
+type RuleError struct {
+	Line int    // The number of the line, from 1
+	Text string // The text of the line
+	Err  error  // The failure, which is a *PatternError
+}
+
+func (e *RuleError) Error() string {
+
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *RuleError) Unwrap() error {
+
+	return e.Err
+}
+
+// FlagError describes a flag that is not recognised: either a name that
+// is not that of any flag - including an empty name, as between the
+// separators of "IgnoreCase||PathMode" - or a value that includes flags
+// that have no name.
+type FlagError struct {
+	Name  string // The unrecognised name, if Value is 0
+	Value uint64 // The unrecognised flags, if any
+}
+
+func (e *FlagError) Error() string {
+
+	switch {
+
+	case 0 != e.Value:
+
+		return fmt.Sprintf("unknown flags 0x%x", e.Value)
+	case 0 == len(e.Name):
+
+		return "empty flag name"
+	default:
+
+		return fmt.Sprintf("unknown flag '%s'", e.Name)
+	}
+}
+
+// ArgumentError describes an argument that moderates behaviour - as passed
+// to Match(), Compile(), and so on - that is not valid, such as one of a
+// type that is neither a flag nor an Option.
+type ArgumentError struct {
+	Index  int    // The index of the argument
+	Value  any    // The argument, or the invalid value it specifies
+	Reason string // The reason that the argument is not valid
+}
+
+func (e *ArgumentError) Error() string {
+
+	return fmt.Sprintf("invalid argument at index %d: %s", e.Index, e.Reason)
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal functions
+ */
+
+func make_pattern_error(pattern string, offset int, kind PatternErrorKind) *PatternError {
+
+	return &PatternError{
+		Pattern:    pattern,
+		Offset:     offset,
+		RuneOffset: utf8.RuneCountInString(pattern[:offset]),
+		Kind:       kind,
+	}
+}
+
+func make_template_error(template string, offset int, kind TemplateErrorKind) *TemplateError {
+
+	return &TemplateError{
+		Template:   template,
+		Offset:     offset,
+		RuneOffset: utf8.RuneCountInString(template[:offset]),
+		Kind:       kind,
+	}
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
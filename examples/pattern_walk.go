@@ -7,8 +7,10 @@ import (
 	shwild "github.com/synesissoftware/shwild.Go"
 
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 const (
@@ -70,85 +72,80 @@ func main() {
 		os.Exit(1)
 	} else {
 
-		// gather the remaining values and convert them into
-		// compiled-patterns
+		// gather the remaining values as glob patterns, in which a pattern
+		// that does not contain a separator is matched against the name of
+		// an entry at any depth
 
 		var directory string
-		var patterns []shwild.CompiledPattern
+		var patterns []string
 
 		directory = args.Values[0].Value
 
 		for _, value := range args.Values[1:] {
 
 			pattern := value.Value
-			cp, err := shwild.Compile(pattern)
-			if err != nil {
-
-				fmt.Fprintf(os.Stderr, "%s: invalid pattern '%s': %v\n", args.ProgramName, pattern, err)
 
-				os.Exit(1)
-			} else {
+			if !strings.Contains(pattern, "/") {
 
-				patterns = append(patterns, cp)
+				pattern = "**/" + pattern
 			}
+
+			patterns = append(patterns, pattern)
 		}
 
 		process(directory, patterns, flags, args.ProgramName)
 	}
 }
 
-func process(directory string, patterns []shwild.CompiledPattern, flags ProcessFlag, program_name string) {
+func process(directory string, patterns []string, flags ProcessFlag, program_name string) {
 
-	err := filepath.Walk(directory, func(path string, fi os.FileInfo, err error) error {
+	// hidden files - and those in hidden directories - are excluded by
+	// requiring a leading period to be matched explicitly
 
-		if fi.IsDir() {
+	pattern_flags := shwild.AllowBraceAlternation
 
-			return nil
-		}
+	if 0 == (ProcessFlag_ShowHidden & flags) {
 
-		if 0 == (ProcessFlag_ShowHidden&flags) && is_hidden(path, fi) {
+		pattern_flags |= shwild.ExplicitLeadingPeriod
+	}
 
-			return nil
-		}
+	fsys := os.DirFS(directory)
+	found := make(map[string]bool)
 
-		// see if any of the patterns are matched
+	for _, pattern := range patterns {
 
-		match_any := false
+		err := shwild.WalkGlob(fsys, pattern, func(path string, d fs.DirEntry, err error) error {
 
-		for _, p := range patterns {
+			if err != nil {
 
-			matched, err := p.Match(fi.Name())
+				fmt.Fprintf(os.Stderr, "%s: cannot read '%s': %v\n", program_name, path, err)
 
-			if matched && err == nil {
+				return nil
+			}
 
-				match_any = true
+			if d.IsDir() || found[path] {
 
-				break
+				return nil
 			}
-		}
 
-		if !match_any {
+			found[path] = true
 
-			return nil
-		}
-
-		fmt.Fprintf(os.Stdout, "found '%s' %d bytes\n", path, fi.Size())
+			fi, err := d.Info()
+			if err != nil {
 
-		return nil
-	})
+				return nil
+			}
 
-	if err != nil {
+			fmt.Fprintf(os.Stdout, "found '%s' %d bytes\n", filepath.Join(directory, filepath.FromSlash(path)), fi.Size())
 
-		fmt.Fprintf(os.Stderr, "%s: search of '%s' failed: %v\n", program_name, directory, err)
-	}
-}
+			return nil
+		}, pattern_flags)
 
-func is_hidden(path string, fi os.FileInfo) bool {
+		if err != nil {
 
-	if '.' == path[0] {
+			fmt.Fprintf(os.Stderr, "%s: search of '%s' for '%s' failed: %v\n", program_name, directory, pattern, err)
 
-		return true
+			os.Exit(1)
+		}
 	}
-
-	return '.' == fi.Name()[0]
 }
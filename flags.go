@@ -1,15 +1,54 @@
-// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2025
+// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2026
 // Matthew Wilson and Synesis Information Systems. All rights reserved. Use
 // of this source code is governed by a BSD-style license that can be found
 // in the LICENSE file.
 
 /*
  * Created: 17th June 2005
- * Updated: 24th February 2025
+ * Updated: 18th October 2026
  */
 
 package shwild
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * API types
+ */
+
+// Flags is a combination of the flags that moderate the behaviour of a
+// pattern, which may be passed - as may the untyped flag constants - as
+// an argument to Match(), Compile(), and so on.
+type Flags uint64
+
+// String obtains the names of the flags, in order of value, separated by
+// '|', as in "IgnoreCase|PathMode", or "0" if there are none. Any flags
+// that have no name are represented in hexadecimal.
+func (f Flags) String() string {
+
+	if 0 == f {
+
+		return "0"
+	}
+
+	names, unknown := names_of_flags(uint64(f))
+
+	if 0 != unknown {
+
+		names = append(names, fmt.Sprintf("0x%x", unknown))
+	}
+
+	return strings.Join(names, "|")
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * constants
+ */
+
 const (
 
 	// Suppresses the recognition of ranges. [ and ] are treated as literal
@@ -24,35 +63,194 @@ const (
 	SuppressRangeContinuumSupport
 
 	// Suppresses the recognition of reverse range continua, i.e. [9-0],
-	// [M-D]
+	// [M-D], in which case the characters are treated literally
 	SuppressRangeContinuumHighlowSupport
 
 	// Suppresses the recognition of cross-case range continua, i.e. [h-J]
-	// === [hijHIJ]
+	// === [hijHIJ], in which case the continuum is ordinal
 	SuppressRangeContinuumCrosscaseSupport
 
-	// Suppresses the recognition of ? and * as literal inside range
+	// Suppresses the recognition of ? and * as literal inside range, in
+	// which case their presence is a parse error
 	SuppressRangeLiteralWildcard
 
 	// Suppresses the recognition of leading/trailing hyphens as literal
-	// inside range
+	// inside range, in which case their presence is a parse error
 	SuppressRangeLeadtrailLiteralHyphen
 
 	// Suppresses the use of a leading ^ to mean not any of the following,
 	// i.e. [^0-9] means do not match a digit
 	SuppressRangeNot
 
-	// Comparison is case-insensitive
+	// Comparison is case-insensitive, according to Unicode simple case
+	// folding, e.g. 'k' === 'K' === '\u212A' (KELVIN SIGN)
 	IgnoreCase
 
-	// Treats [ and ] as literal inside range. ] only literal if immediately
-	// preceeds closing ]
+	// Treats ] as literal inside range if it immediately preceeds the
+	// closing ], as in [a]]. Regardless of this flag, [ is always literal
+	// inside range, as in [a[], as is ] if it is the first character in the
+	// range, as in []abc]
 	AllowRangeLiteralBracket
 
 	// Allows quantification of the wildcards, with trailing escaped
 	// numbers, as in [a-Z]\2-10. All chars in 0-9- become range specifiers.
-	// These are separated from actual pattern digits by []
+	// These are separated from actual pattern digits by []. A quantifier
+	// applies to the preceding literal character, ? or range, and may be
+	// of the forms \n (exactly n), \n-m (n to m), \n- (n or more), and \-m
+	// (up to m)
 	AllowRangeQuantification
+
+	// Allows alternation with braces, as in *.{jpg,jpeg,png}, which
+	// matches any of its comma-separated branches. Branches may be empty,
+	// as in a{,b}, and may contain wildcards, ranges and further
+	// alternations. Commas and braces may be escaped. A brace that has no
+	// matching closing brace, or whose contents have no separating comma,
+	// as in {abc}, is literal
+	AllowBraceAlternation
+
+	// Matches paths, in which case *, ? and ranges (including not-ranges)
+	// do not match the path separator /, and ** as a whole path segment
+	// matches zero or more directories, so that **/*.go matches main.go
+	// and a/b/main.go, src/**/*.go matches src/main.go and src/a/main.go,
+	// and src/** matches src and everything beneath it. A ** at the start
+	// or end of a branch of an alternation is a whole segment if the
+	// alternation starts or ends one, as in {**,test}/*.go; but a ** that
+	// adjoins an alternation from outside, as in {a/,b}**, is not, nor is
+	// one that is separated only by a separator from another alternation,
+	// as in {a,**}/{**,b}, which may differ from the alternation's expansion
+	PathMode
+
+	// Requires a leading period in the subject - or, in path mode, a
+	// period that follows a separator - to be matched explicitly by a
+	// literal period in the pattern, rather than by *, ?, a range, or **,
+	// so that * does not match .bashrc, in the manner of fnmatch()'s
+	// FNM_PERIOD
+	ExplicitLeadingPeriod
+
+	// Causes CompiledPattern.Find() and CompiledPattern.FindAll() to obtain
+	// the longest of the matches that begin at the leftmost position,
+	// rather than the shortest
+	LeftmostLongest
+)
+
+const (
+	_PATH_SEPARATOR = '/'
 )
 
+/* /////////////////////////////////////////////////////////////////////////
+ * API functions
+ */
+
+// ParseFlags parses the names of flags separated by '|', as in
+// "IgnoreCase|PathMode", into the flags. A term may instead be a value in
+// hexadecimal, as in "LeftmostLongest|0x10000000000", so that any form
+// obtained by Flags.String() - which so represents flags that have no name
+// - is parsed. Whitespace around each term is ignored, and an empty string,
+// or "0", obtains no flags. An unrecognised term is reported as a
+// *FlagError.
+func ParseFlags(s string) (Flags, error) {
+
+	var flags Flags
+
+	if s = strings.TrimSpace(s); 0 == len(s) || "0" == s {
+
+		return 0, nil
+	}
+
+	for _, term := range strings.Split(s, "|") {
+
+		flag, err := flag_of_term(strings.TrimSpace(term))
+
+		if nil != err {
+
+			return 0, err
+		}
+
+		flags |= Flags(flag)
+	}
+
+	return flags, nil
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal variables
+ */
+
+// The name of each flag, in order of value.
+var flag_names = []struct {
+	flag uint64
+	name string
+}{
+	{SuppressRangeSupport, "SuppressRangeSupport"},
+	{SuppressBackslashEscape, "SuppressBackslashEscape"},
+	{SuppressRangeContinuumSupport, "SuppressRangeContinuumSupport"},
+	{SuppressRangeContinuumHighlowSupport, "SuppressRangeContinuumHighlowSupport"},
+	{SuppressRangeContinuumCrosscaseSupport, "SuppressRangeContinuumCrosscaseSupport"},
+	{SuppressRangeLiteralWildcard, "SuppressRangeLiteralWildcard"},
+	{SuppressRangeLeadtrailLiteralHyphen, "SuppressRangeLeadtrailLiteralHyphen"},
+	{SuppressRangeNot, "SuppressRangeNot"},
+	{IgnoreCase, "IgnoreCase"},
+	{AllowRangeLiteralBracket, "AllowRangeLiteralBracket"},
+	{AllowRangeQuantification, "AllowRangeQuantification"},
+	{AllowBraceAlternation, "AllowBraceAlternation"},
+	{PathMode, "PathMode"},
+	{ExplicitLeadingPeriod, "ExplicitLeadingPeriod"},
+	{LeftmostLongest, "LeftmostLongest"},
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal functions
+ */
+
+// Obtains the names of the flags, in order of value, along with any flags
+// that have no name.
+func names_of_flags(flags uint64) ([]string, uint64) {
+
+	var names []string
+
+	for _, fn := range flag_names {
+
+		if 0 != (fn.flag & flags) {
+
+			names = append(names, fn.name)
+			flags &^= fn.flag
+		}
+	}
+
+	return names, flags
+}
+
+// Obtains the flag with the given name, or a *FlagError.
+func flag_of_name(name string) (uint64, error) {
+
+	for _, fn := range flag_names {
+
+		if fn.name == name {
+
+			return fn.flag, nil
+		}
+	}
+
+	return 0, &FlagError{Name: name}
+}
+
+// Obtains the flags denoted by the given term - either the name of a flag,
+// or a value in hexadecimal, as in "0x10000000000" - or a *FlagError.
+func flag_of_term(term string) (uint64, error) {
+
+	if digits, ok := strings.CutPrefix(term, "0x"); ok {
+
+		flags, err := strconv.ParseUint(digits, 16, 64)
+
+		if nil != err {
+
+			return 0, &FlagError{Name: term}
+		}
+
+		return flags, nil
+	}
+
+	return flag_of_name(term)
+}
+
 /* ///////////////////////////// end of file //////////////////////////// */
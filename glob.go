@@ -0,0 +1,563 @@
+// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2026
+// Matthew Wilson and Synesis Information Systems. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+/*
+ * Created: 18th October 2026
+ * Updated: 18th October 2026
+ */
+
+package shwild
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"strings"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal types
+ */
+
+// glob_segment structure
+//
+// A segment of a glob pattern - the part between separators - which is
+// either ** (matching any number of directories), literal (naming a single
+// entry, which need not be sought by reading its directory), or a pattern
+// that is matched against each entry of its directory.
+
+type glob_segment struct {
+	dirs    bool
+	literal bool
+	name    string
+	cp      CompiledPattern
+}
+
+// glob_item structure
+//
+// An item found by a step of a glob, which is either an entry that may
+// match the pattern, the failure to read a directory, or a task: the
+// directory path within which are sought the entries that match segments.
+
+type glob_item struct {
+	path     string
+	entry    fs.DirEntry
+	err      error
+	segments []glob_segment
+}
+
+// globber structure
+//
+// The state of a single glob of a file system.
+
+type globber struct {
+	fsys   fs.FS
+	cp     CompiledPattern
+	flags  uint64
+	fn     fs.WalkDirFunc
+	seen   map[string]bool
+	failed map[string]bool
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * API functions
+ */
+
+// Glob obtains the names of all entries of fsys that match pattern,
+// subject to additional arguments that moderate behaviour - which apply in
+// addition to PathMode - in lexical order. The pattern is of the form of
+// the names of fsys: unrooted, with / as its separator. A failure to read a
+// directory is returned, along with no names.
+func Glob(fsys fs.FS, pattern string, args ...any) ([]string, error) {
+
+	var matches []string
+
+	err := WalkGlob(fsys, pattern, func(path string, d fs.DirEntry, err error) error {
+
+		if nil != err {
+
+			return err
+		}
+
+		matches = append(matches, path)
+
+		return nil
+	}, args...)
+
+	if nil != err {
+
+		return nil, err
+	}
+
+	slices.Sort(matches)
+
+	return matches, nil
+}
+
+// WalkGlob calls fn for each entry of fsys that matches pattern - as does
+// Glob() - as it is found, rather than obtaining them all. Each directory
+// is read in lexical order, and only those directories are read that may
+// contain a match, so that a literal segment of the pattern (such as "src"
+// in "src/*.go") is sought directly. A segment ** matches any number of
+// directories, but does not follow symbolic links.
+//
+// fn is called with a non-nil error - and the name of the directory - if
+// a directory cannot be read, and the walk continues if it returns nil. If
+// fn returns fs.SkipAll the walk stops; if it returns any other error the
+// walk stops and the error is returned.
+func WalkGlob(fsys fs.FS, pattern string, fn fs.WalkDirFunc, args ...any) error {
+
+	g, roots, err := make_globber(fsys, pattern, fn, args...)
+
+	if nil != err {
+
+		return err
+	}
+
+	for _, segments := range roots {
+
+		if err := g.walk(".", segments); nil != err {
+
+			if errors.Is(err, fs.SkipAll) {
+
+				return nil
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal functions
+ */
+
+// Creates a globber for the pattern, subject to additional arguments that
+// moderate behaviour, and obtains the segments of each of the patterns to
+// which it expands (see expand_path_alternations()), of which there are
+// none if the pattern is empty.
+func make_globber(fsys fs.FS, pattern string, fn fs.WalkDirFunc, args ...any) (*globber, [][]glob_segment, error) {
+
+	o, err := parse_options_(args...)
+
+	if nil == err {
+
+		err = o.require_slash_separator()
+	}
+
+	if nil != err {
+
+		return nil, nil, err
+	}
+
+	flags := o.flags | PathMode
+
+	cp, err := Compile(pattern, flags)
+
+	if nil != err {
+
+		return nil, nil, err
+	}
+
+	g := &globber{
+		fsys:   fsys,
+		cp:     cp,
+		flags:  flags,
+		fn:     fn,
+		seen:   make(map[string]bool),
+		failed: make(map[string]bool),
+	}
+
+	if 0 == len(pattern) {
+
+		return g, nil, nil
+	}
+
+	var roots [][]glob_segment
+
+	for _, p := range expand_path_alternations(pattern, flags) {
+
+		segments, err := make_glob_segments(p, flags)
+
+		if nil != err {
+
+			return nil, nil, &fs.PathError{Op: "glob", Path: pattern, Err: err}
+		}
+
+		roots = append(roots, segments)
+	}
+
+	return g, roots, nil
+}
+
+// Finds, depth first, the entries within dir that match the segments.
+func (g *globber) walk(dir string, segments []glob_segment) error {
+
+	for _, item := range g.step(dir, segments) {
+
+		var err error
+
+		switch {
+
+		case nil != item.segments:
+
+			err = g.walk(item.path, item.segments)
+		case nil != item.err:
+
+			if g.accept_failure(item.path) {
+
+				err = g.fn(item.path, nil, item.err)
+			}
+		default:
+
+			err = g.emit(item.path, item.entry)
+		}
+
+		if nil != err {
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Obtains, in order, the items within dir - reading no directory other
+// than dir - that match the first of segments: each entry that may match
+// the pattern, and each task for a directory within which may be entries
+// that match the remaining segments.
+func (g *globber) step(dir string, segments []glob_segment) []glob_item {
+
+	seg, rest := segments[0], segments[1:]
+
+	var items []glob_item
+
+	// adds the entry, which matches seg. An entry - whether a directory or
+	// not - that precedes a trailing ** is itself a match, as it is for
+	// CompiledPattern.Match()
+
+	add := func(name string, d fs.DirEntry) {
+
+		if 0 == len(rest) || (1 == len(rest) && rest[0].dirs) {
+
+			items = append(items, glob_item{path: name, entry: d})
+		}
+
+		if 0 != len(rest) && d.IsDir() {
+
+			items = append(items, glob_item{path: name, segments: rest})
+		}
+	}
+
+	switch {
+
+	case seg.dirs:
+
+		// ** matches no directories, and each directory beneath dir
+
+		if 0 != len(rest) {
+
+			items = append(items, glob_item{path: dir, segments: rest})
+		}
+
+		entries, err := fs.ReadDir(g.fsys, dir)
+
+		if nil != err {
+
+			return append(items, glob_item{path: dir, err: err})
+		}
+
+		for _, e := range entries {
+
+			if g.is_hidden(e.Name()) {
+
+				continue
+			}
+
+			name := join_path(dir, e.Name())
+
+			if 0 == len(rest) {
+
+				items = append(items, glob_item{path: name, entry: e})
+			}
+
+			if e.IsDir() {
+
+				items = append(items, glob_item{path: name, segments: segments})
+			}
+		}
+	case seg.literal:
+
+		name := join_path(dir, seg.name)
+
+		if fi, err := fs.Stat(g.fsys, name); nil == err {
+
+			add(name, fs.FileInfoToDirEntry(fi))
+		}
+	default:
+
+		entries, err := fs.ReadDir(g.fsys, dir)
+
+		if nil != err {
+
+			return []glob_item{{path: dir, err: err}}
+		}
+
+		for _, e := range entries {
+
+			if matched, _ := seg.cp.Match(e.Name()); matched {
+
+				add(join_path(dir, e.Name()), e)
+			}
+		}
+	}
+
+	return items
+}
+
+// Determines whether the name may not be matched by **, because it begins
+// with a period and ExplicitLeadingPeriod is specified.
+func (g *globber) is_hidden(name string) bool {
+
+	return 0 != (ExplicitLeadingPeriod&g.flags) && strings.HasPrefix(name, ".")
+}
+
+// Reports the path, if it matches the whole pattern and has not already
+// been reported.
+func (g *globber) emit(path string, d fs.DirEntry) error {
+
+	if !g.accept(path) {
+
+		return nil
+	}
+
+	return g.fn(path, d, nil)
+}
+
+// Determines whether the path matches the whole pattern and has not
+// already been accepted.
+func (g *globber) accept(path string) bool {
+
+	if g.seen[path] {
+
+		return false
+	}
+
+	if matched, _ := g.cp.Match(path); !matched {
+
+		return false
+	}
+
+	g.seen[path] = true
+
+	return true
+}
+
+// Determines whether the failure to read the directory has not already
+// been accepted, since a directory may be read more than once.
+func (g *globber) accept_failure(dir string) bool {
+
+	if g.failed[dir] {
+
+		return false
+	}
+
+	g.failed[dir] = true
+
+	return true
+}
+
+func join_path(dir, name string) string {
+
+	if "." == dir {
+
+		return name
+	}
+
+	return dir + "/" + name
+}
+
+// Expands each alternation that contains a separator or a ** - as in
+// "{src,test/unit}/*.go" or "{**,src}/*.go" - into the pattern for each
+// branch, so that the pattern may then be split into segments.
+func expand_path_alternations(pattern string, flags uint64) []string {
+
+	if 0 == (AllowBraceAlternation & flags) {
+
+		return []string{pattern}
+	}
+
+	for ix := 0; len(pattern) > ix; ix++ {
+
+		switch pattern[ix] {
+
+		case '\\':
+
+			if 0 == (SuppressBackslashEscape & flags) {
+
+				ix++
+			}
+		case '[':
+
+			if 0 == (SuppressRangeSupport & flags) {
+
+				if end := scan_range(pattern, ix, flags); end >= 0 {
+
+					ix = end
+				}
+			}
+		case '{':
+
+			bounds := scan_alternation(pattern, ix, flags)
+
+			if nil == bounds {
+
+				continue
+			}
+
+			end := bounds[len(bounds)-1]
+
+			if !strings.Contains(pattern[ix:end], "/") && !strings.Contains(pattern[ix:end], "**") {
+
+				ix = end
+
+				continue
+			}
+
+			var patterns []string
+
+			for i := 0; len(bounds)-1 != i; i++ {
+
+				branch := pattern[:ix] + pattern[bounds[i]+1:bounds[i+1]] + pattern[end+1:]
+
+				patterns = append(patterns, expand_path_alternations(branch, flags)...)
+			}
+
+			return patterns
+		}
+	}
+
+	return []string{pattern}
+}
+
+// Splits the pattern into its segments, each of which must be non-empty.
+func make_glob_segments(pattern string, flags uint64) ([]glob_segment, error) {
+
+	var parts []string
+
+	beg := 0
+
+	for ix := 0; len(pattern) > ix; ix++ {
+
+		switch pattern[ix] {
+
+		case '\\':
+
+			if 0 == (SuppressBackslashEscape & flags) {
+
+				ix++
+			}
+		case '[':
+
+			if 0 == (SuppressRangeSupport & flags) {
+
+				if end := scan_range(pattern, ix, flags); end >= 0 {
+
+					ix = end
+				}
+			}
+		case _PATH_SEPARATOR:
+
+			parts = append(parts, pattern[beg:ix])
+			beg = ix + 1
+		}
+	}
+
+	parts = append(parts, pattern[beg:])
+
+	segments := make([]glob_segment, len(parts))
+
+	for ix, part := range parts {
+
+		seg, err := make_glob_segment(part, flags)
+
+		if nil != err {
+
+			return nil, err
+		}
+
+		segments[ix] = seg
+	}
+
+	return segments, nil
+}
+
+func make_glob_segment(s string, flags uint64) (glob_segment, error) {
+
+	if 0 == len(s) {
+
+		return glob_segment{}, fs.ErrInvalid
+	}
+
+	if "**" == s {
+
+		return glob_segment{dirs: true}, nil
+	}
+
+	if 0 == (IgnoreCase & flags) {
+
+		if name, ok := literal_text(s, flags); ok {
+
+			return glob_segment{literal: true, name: name}, nil
+		}
+	}
+
+	cp, err := Compile(s, flags)
+
+	if nil != err {
+
+		return glob_segment{}, err
+	}
+
+	return glob_segment{cp: cp}, nil
+}
+
+// Obtains the text matched by the pattern, if it is entirely literal.
+func literal_text(pattern string, flags uint64) (string, bool) {
+
+	nodes, err := parse_nodes(pattern, flags, _PATH_SEPARATOR)
+
+	if nil != err {
+
+		return "", false
+	}
+
+	var sb strings.Builder
+
+	for _, n := range nodes {
+
+		switch n.node_type {
+
+		case _NODE_LITERAL:
+
+			if n.is_quantified() {
+
+				return "", false
+			}
+
+			sb.WriteString(n.data)
+		case _NODE_END:
+		default:
+
+			return "", false
+		}
+	}
+
+	return sb.String(), true
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
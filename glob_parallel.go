@@ -0,0 +1,396 @@
+// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2026
+// Matthew Wilson and Synesis Information Systems. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+/*
+ * Created: 18th October 2026
+ * Updated: 18th October 2026
+ */
+
+package shwild
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"runtime"
+	"sync"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * API types
+ */
+
+// ErrorPolicy indicates how GlobParallel() responds to a failure to read a
+// directory.
+type ErrorPolicy int
+
+const (
+	// The failure is reported, and the glob stops
+	StopOnError ErrorPolicy = iota
+
+	// The failure is reported, and the glob continues
+	ContinueOnError
+)
+
+func (p ErrorPolicy) String() string {
+
+	switch p {
+
+	case StopOnError:
+		return "StopOnError"
+	case ContinueOnError:
+		return "ContinueOnError"
+	}
+
+	return fmt.Sprintf("<%T %d>", p, p)
+}
+
+// GlobOptions moderates the behaviour of GlobParallel().
+type GlobOptions struct {
+	Workers     int         // The number of directories read concurrently; if 0, runtime.GOMAXPROCS(0)
+	Ordered     bool        // The results are in the order in which WalkGlob() would report them
+	ErrorPolicy ErrorPolicy // The response to a failure to read a directory
+}
+
+// GlobResult is a result of GlobParallel(): either an entry that matches
+// the pattern, or - if Err is non-nil - the failure to read the directory
+// Path.
+type GlobResult struct {
+	Path  string
+	Entry fs.DirEntry
+	Err   error
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal types
+ */
+
+// glob_task structure
+//
+// A task of a parallel glob, which is to perform a step (see
+// globber.step()), after which done is closed and - if the results are
+// ordered - the items of the step are available, along with the task for
+// each item that is itself a task.
+
+type glob_task struct {
+	dir      string
+	segments []glob_segment
+	items    []glob_item
+	subtasks []*glob_task
+	done     chan struct{}
+}
+
+// glob_queue structure
+//
+// The tasks of a parallel glob that are yet to be performed, which are
+// taken most recent first, so that the glob proceeds (approximately) depth
+// first. The queue is finished when it is closed or when every task that
+// has been added has been performed.
+
+type glob_queue struct {
+	mu      sync.Mutex
+	cond    sync.Cond
+	tasks   []*glob_task
+	pending int
+	closed  bool
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * API functions
+ */
+
+// GlobParallel finds the entries of fsys that match pattern - as does
+// WalkGlob() - subject to additional arguments that moderate behaviour,
+// reading directories concurrently, and obtains a channel on which each is
+// sent as it is found (or, if options.Ordered, as soon as every result that
+// precedes it has been sent). An invalid pattern is returned as an error.
+//
+// The channel is closed when the glob is complete, when ctx is done, or -
+// according to options.ErrorPolicy - after the first failure to read a
+// directory is sent. The caller must either receive from the channel until
+// it is closed, or cancel ctx.
+func GlobParallel(ctx context.Context, fsys fs.FS, pattern string, options GlobOptions, args ...any) (<-chan GlobResult, error) {
+
+	g, roots, err := make_globber(fsys, pattern, nil, args...)
+
+	if nil != err {
+
+		return nil, err
+	}
+
+	workers := options.Workers
+
+	if workers <= 0 {
+
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	out := make(chan GlobResult)
+	found := make(chan glob_item, workers)
+
+	q := &glob_queue{}
+	q.cond.L = &q.mu
+
+	stop := context.AfterFunc(ctx, q.close)
+
+	// the root task - which is performed already - has a subtask for each
+	// of the patterns to which the pattern expands
+
+	root := make_glob_task(".", nil)
+
+	close(root.done)
+
+	for _, segments := range roots {
+
+		t := make_glob_task(".", segments)
+
+		root.subtasks = append(root.subtasks, t)
+		root.items = append(root.items, glob_item{path: ".", segments: segments})
+	}
+
+	q.add(root.subtasks)
+
+	var wg sync.WaitGroup
+
+	for range workers {
+
+		wg.Add(1)
+
+		go func() {
+
+			defer wg.Done()
+
+			for t := q.take(); nil != t; t = q.take() {
+
+				g.perform(ctx, q, t, options.Ordered, found)
+			}
+		}()
+	}
+
+	go func() {
+
+		wg.Wait()
+		close(found)
+	}()
+
+	go func() {
+
+		defer close(out)
+		defer wg.Wait()
+		defer stop()
+		defer cancel()
+
+		// sends the item, determining whether the glob continues
+
+		send := func(item glob_item) bool {
+
+			var r GlobResult
+
+			if nil != item.err {
+
+				if !g.accept_failure(item.path) {
+
+					return true
+				}
+
+				r = GlobResult{Path: item.path, Err: item.err}
+			} else if g.accept(item.path) {
+
+				r = GlobResult{Path: item.path, Entry: item.entry}
+			} else {
+
+				return true
+			}
+
+			select {
+
+			case out <- r:
+			case <-ctx.Done():
+
+				return false
+			}
+
+			return nil == item.err || ContinueOnError == options.ErrorPolicy
+		}
+
+		if options.Ordered {
+
+			g.visit(ctx, root, send)
+		} else {
+
+			for item := range found {
+
+				if !send(item) {
+
+					break
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal functions
+ */
+
+func make_glob_task(dir string, segments []glob_segment) *glob_task {
+
+	return &glob_task{
+		dir:      dir,
+		segments: segments,
+		done:     make(chan struct{}),
+	}
+}
+
+// Performs the task, adding to the queue a task for each item that is a
+// task and - unless the results are ordered, in which case they are kept
+// by the task - sending each other item to found.
+func (g *globber) perform(ctx context.Context, q *glob_queue, t *glob_task, ordered bool, found chan<- glob_item) {
+
+	defer q.finish()
+
+	items := g.step(t.dir, t.segments)
+	subtasks := make([]*glob_task, len(items))
+
+	var tasks []*glob_task
+
+	for ix, item := range items {
+
+		if nil != item.segments {
+
+			subtasks[ix] = make_glob_task(item.path, item.segments)
+			tasks = append(tasks, subtasks[ix])
+		}
+	}
+
+	q.add(tasks)
+
+	if ordered {
+
+		t.items, t.subtasks = items, subtasks
+
+		close(t.done)
+
+		return
+	}
+
+	for _, item := range items {
+
+		if nil != item.segments {
+
+			continue
+		}
+
+		select {
+
+		case found <- item:
+		case <-ctx.Done():
+
+			return
+		}
+	}
+}
+
+// Sends, depth first, each item of the task - once it is performed - and
+// of its subtasks, determining whether the glob continues.
+func (g *globber) visit(ctx context.Context, t *glob_task, send func(glob_item) bool) bool {
+
+	select {
+
+	case <-t.done:
+	case <-ctx.Done():
+
+		return false
+	}
+
+	for ix, item := range t.items {
+
+		if st := t.subtasks[ix]; nil != st {
+
+			if !g.visit(ctx, st, send) {
+
+				return false
+			}
+
+			continue
+		}
+
+		if !send(item) {
+
+			return false
+		}
+	}
+
+	return true
+}
+
+// Adds the tasks, such that the first is taken first.
+func (q *glob_queue) add(tasks []*glob_task) {
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for ix := len(tasks) - 1; ix >= 0; ix-- {
+
+		q.tasks = append(q.tasks, tasks[ix])
+	}
+
+	q.pending += len(tasks)
+
+	q.cond.Broadcast()
+}
+
+// Takes the most recently added task, waiting until there is one, or
+// obtains nil if the queue is finished.
+func (q *glob_queue) take() *glob_task {
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for !q.closed && 0 == len(q.tasks) && 0 != q.pending {
+
+		q.cond.Wait()
+	}
+
+	if q.closed || 0 == len(q.tasks) {
+
+		return nil
+	}
+
+	t := q.tasks[len(q.tasks)-1]
+	q.tasks = q.tasks[:len(q.tasks)-1]
+
+	return t
+}
+
+// Records that a task that was taken has been performed.
+func (q *glob_queue) finish() {
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pending--
+
+	if 0 == q.pending {
+
+		q.cond.Broadcast()
+	}
+}
+
+func (q *glob_queue) close() {
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closed = true
+
+	q.cond.Broadcast()
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
@@ -0,0 +1,214 @@
+package shwild_test
+
+import (
+	shwild "github.com/synesissoftware/shwild.Go"
+
+	"context"
+	"errors"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal functions
+ */
+
+func walk_glob(t *testing.T, fsys fs.FS, pattern string, flags int) []string {
+
+	var paths []string
+
+	err := shwild.WalkGlob(fsys, pattern, func(path string, d fs.DirEntry, err error) error {
+
+		if err != nil {
+
+			return err
+		}
+
+		paths = append(paths, path)
+
+		return nil
+	}, flags)
+	if err != nil {
+
+		t.Fatalf("WalkGlob('%s') failed: %v", pattern, err)
+	}
+
+	return paths
+}
+
+func collect_results(results <-chan shwild.GlobResult) (paths []string, errs []error) {
+
+	for r := range results {
+
+		if nil != r.Err {
+
+			errs = append(errs, r.Err)
+		} else {
+
+			paths = append(paths, r.Path)
+		}
+	}
+
+	return
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * tests
+ */
+
+func Test_GlobParallel_agrees_with_WalkGlob(t *testing.T) {
+
+	fsys := make_test_fs()
+
+	for _, tc := range []struct {
+		pattern string
+		flags   int
+	}{
+		{"*", 0},
+		{"**", 0},
+		{"**/*.go", 0},
+		{"**/*.go", shwild.ExplicitLeadingPeriod},
+		{"src/**/*.go", 0},
+		{"src/internal/**", 0},
+		{"*/*", 0},
+		{"{src,test/unit}/*_test.go", shwild.AllowBraceAlternation},
+		{"{*,src/*}.go", shwild.AllowBraceAlternation},
+		{"missing/*", 0},
+	} {
+
+		expected := walk_glob(t, fsys, tc.pattern, tc.flags)
+
+		for _, workers := range []int{0, 1, 4, 16} {
+
+			results, err := shwild.GlobParallel(context.Background(), fsys, tc.pattern, shwild.GlobOptions{Workers: workers, Ordered: true}, tc.flags)
+			if err != nil {
+
+				t.Fatalf("GlobParallel('%s') failed: %v", tc.pattern, err)
+			}
+
+			if actual, errs := collect_results(results); !slices.Equal(expected, actual) || 0 != len(errs) {
+
+				t.Errorf("ordered GlobParallel('%s') with %d workers obtained %q and %v; %q expected", tc.pattern, workers, actual, errs, expected)
+			}
+
+			results, _ = shwild.GlobParallel(context.Background(), fsys, tc.pattern, shwild.GlobOptions{Workers: workers}, tc.flags)
+
+			actual, _ := collect_results(results)
+
+			slices.Sort(actual)
+
+			if sorted := slices.Sorted(slices.Values(expected)); !slices.Equal(sorted, actual) {
+
+				t.Errorf("unordered GlobParallel('%s') with %d workers obtained %q; %q expected", tc.pattern, workers, actual, sorted)
+			}
+		}
+	}
+}
+
+func Test_GlobParallel_with_ErrorPolicy(t *testing.T) {
+
+	read_err := errors.New("read failed")
+
+	fsys := failing_fs{MapFS: make_test_fs(), dir: "src/internal", err: read_err}
+
+	for _, ordered := range []bool{false, true} {
+
+		results, _ := shwild.GlobParallel(context.Background(), fsys, "**/*.go", shwild.GlobOptions{Workers: 4, Ordered: ordered, ErrorPolicy: shwild.ContinueOnError})
+
+		paths, errs := collect_results(results)
+
+		if 1 != len(errs) || !errors.Is(errs[0], read_err) {
+
+			t.Errorf("GlobParallel() with ContinueOnError obtained errors %v; [%v] expected", errs, read_err)
+		}
+
+		slices.Sort(paths)
+
+		if expected := []string{".hidden.go", "main.go", "src/.cache/x.go", "src/api.go", "src/api_test.go", "test/unit/a_test.go"}; !slices.Equal(expected, paths) {
+
+			t.Errorf("GlobParallel() with ContinueOnError obtained %q; %q expected", paths, expected)
+		}
+
+		results, _ = shwild.GlobParallel(context.Background(), fsys, "**/*.go", shwild.GlobOptions{Workers: 4, Ordered: ordered, ErrorPolicy: shwild.StopOnError})
+
+		var last shwild.GlobResult
+
+		n := 0
+
+		for r := range results {
+
+			last = r
+			n++
+		}
+
+		if !errors.Is(last.Err, read_err) {
+
+			t.Errorf("GlobParallel() with StopOnError obtained last result %+v after %d results; error %v expected", last, n, read_err)
+		}
+	}
+}
+
+func Test_GlobParallel_with_cancellation(t *testing.T) {
+
+	fsys := fstest.MapFS{}
+
+	for _, dir := range []string{"a", "b", "c", "d"} {
+
+		for _, sub := range []string{"1", "2", "3", "4", "5", "6", "7", "8"} {
+
+			fsys[dir+"/"+sub+"/file.txt"] = &fstest.MapFile{}
+		}
+	}
+
+	for _, ordered := range []bool{false, true} {
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		results, _ := shwild.GlobParallel(ctx, fsys, "**", shwild.GlobOptions{Workers: 4, Ordered: ordered})
+
+		<-results
+
+		cancel()
+
+		n := 0
+
+		timeout := time.After(5 * time.Second)
+
+	drain:
+		for {
+
+			select {
+
+			case _, ok := <-results:
+
+				if !ok {
+
+					break drain
+				}
+
+				n++
+			case <-timeout:
+
+				t.Fatalf("GlobParallel() did not stop after cancellation")
+			}
+		}
+
+		if n >= 4+32+32-1 {
+
+			t.Errorf("GlobParallel() obtained %d results after cancellation", n)
+		}
+	}
+}
+
+func Test_GlobParallel_with_invalid_pattern(t *testing.T) {
+
+	if _, err := shwild.GlobParallel(context.Background(), make_test_fs(), "[abc", shwild.GlobOptions{}); !errors.Is(err, shwild.UnterminatedRange) {
+
+		t.Errorf("GlobParallel() returned %v; %v expected", err, shwild.UnterminatedRange)
+	}
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
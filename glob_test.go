@@ -0,0 +1,322 @@
+package shwild_test
+
+import (
+	shwild "github.com/synesissoftware/shwild.Go"
+
+	"errors"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal functions
+ */
+
+func make_test_fs() fstest.MapFS {
+
+	return fstest.MapFS{
+		"README.md":                 {},
+		"go.mod":                    {},
+		"main.go":                   {},
+		".hidden.go":                {},
+		"src/api.go":                {},
+		"src/api_test.go":           {},
+		"src/notes.txt":             {},
+		"src/.cache/x.go":           {},
+		"src/internal/util.go":      {},
+		"src/internal/deep/deep.go": {},
+		"test/unit/a_test.go":       {},
+		"test/unit/b.txt":           {},
+		"docs/[draft].md":           {},
+	}
+}
+
+// fs.FS that records the directories read
+type recording_fs struct {
+	fstest.MapFS
+	read []string
+}
+
+func (r *recording_fs) ReadDir(name string) ([]fs.DirEntry, error) {
+
+	r.read = append(r.read, name)
+
+	return r.MapFS.ReadDir(name)
+}
+
+// fs.FS that fails to read the named directory
+type failing_fs struct {
+	fstest.MapFS
+	dir string
+	err error
+}
+
+func (f failing_fs) ReadDir(name string) ([]fs.DirEntry, error) {
+
+	if f.dir == name {
+
+		return nil, f.err
+	}
+
+	return f.MapFS.ReadDir(name)
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * tests
+ */
+
+func Test_Glob(t *testing.T) {
+
+	fsys := make_test_fs()
+
+	for _, tc := range []struct {
+		pattern  string
+		flags    int
+		expected []string
+	}{
+		{"*.go", 0, []string{".hidden.go", "main.go"}},
+		{"*.go", shwild.ExplicitLeadingPeriod, []string{"main.go"}},
+		{"src/*.go", 0, []string{"src/api.go", "src/api_test.go"}},
+		{"src/*_test.go", 0, []string{"src/api_test.go"}},
+		{"*/*.txt", 0, []string{"src/notes.txt"}},
+		{"src/**/*.go", 0, []string{"src/.cache/x.go", "src/api.go", "src/api_test.go", "src/internal/deep/deep.go", "src/internal/util.go"}},
+		{"src/**/*.go", shwild.ExplicitLeadingPeriod, []string{"src/api.go", "src/api_test.go", "src/internal/deep/deep.go", "src/internal/util.go"}},
+		{"**/*_test.go", 0, []string{"src/api_test.go", "test/unit/a_test.go"}},
+		{"src/internal/**", 0, []string{"src/internal", "src/internal/deep", "src/internal/deep/deep.go", "src/internal/util.go"}},
+		{"README.md", 0, []string{"README.md"}},
+		{"readme.md", 0, nil},
+		{"readme.md", shwild.IgnoreCase, []string{"README.md"}},
+		{"missing/*.go", 0, nil},
+		{"main.go/*", 0, nil},
+		{"src", 0, []string{"src"}},
+		{"docs/\\[draft\\].md", 0, []string{"docs/[draft].md"}},
+		{"{src,test/unit}/*_test.go", shwild.AllowBraceAlternation, []string{"src/api_test.go", "test/unit/a_test.go"}},
+		{"src/*.{go,txt}", shwild.AllowBraceAlternation, []string{"src/api.go", "src/api_test.go", "src/notes.txt"}},
+		{"{*,src/*}.go", shwild.AllowBraceAlternation, []string{".hidden.go", "main.go", "src/api.go", "src/api_test.go"}},
+		{"", 0, nil},
+	} {
+
+		matches, err := shwild.Glob(fsys, tc.pattern, tc.flags)
+		if err != nil {
+
+			t.Errorf("Glob('%s') failed: %v", tc.pattern, err)
+
+			continue
+		}
+
+		if !slices.Equal(tc.expected, matches) {
+
+			t.Errorf("Glob('%s') returned %q; %q expected", tc.pattern, matches, tc.expected)
+		}
+	}
+}
+
+func Test_Glob_agrees_with_Match(t *testing.T) {
+
+	fsys := make_test_fs()
+
+	var names []string
+
+	fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+
+		if "." != path {
+
+			names = append(names, path)
+		}
+
+		return nil
+	})
+
+	for _, pattern := range []string{"*", "*/*", "**", "**/*", "*/**/*.go", "?rc/*", "[st]*/**", "**/[!.]*", "{**,test}/*.go", "{src/**,docs}/*", "src/{**,x}/*.go", "{test,src/**}"} {
+
+		for _, flags := range []int{0, shwild.ExplicitLeadingPeriod, shwild.AllowBraceAlternation} {
+
+			var expected []string
+
+			for _, name := range names {
+
+				if matched, _ := shwild.Match(pattern, name, shwild.PathMode|flags); matched {
+
+					expected = append(expected, name)
+				}
+			}
+
+			slices.Sort(expected)
+
+			if actual, _ := shwild.Glob(fsys, pattern, flags); !slices.Equal(expected, actual) {
+
+				t.Errorf("Glob('%s') with flags 0x%x returned %q; %q expected", pattern, flags, actual, expected)
+			}
+		}
+	}
+}
+
+func Test_Glob_agrees_with_Match_for_files_named_as_directories(t *testing.T) {
+
+	fsys := fstest.MapFS{
+		"logs":             {},
+		"src":              {},
+		"data/logs":        {},
+		"data/src/main.go": {},
+		"app/logs/a.log":   {},
+		"app/logs/old/b":   {},
+		".hidden":          {},
+	}
+
+	var names []string
+
+	fs.WalkDir(fsys, ".", func(path string, d fs.DirEntry, err error) error {
+
+		if "." != path {
+
+			names = append(names, path)
+		}
+
+		return nil
+	})
+
+	for _, pattern := range []string{"logs/**", "src/**", "*/**", "**", "**/logs/**", "*/logs/**", "data/*/**", "app/logs/**", "{logs,src}/**"} {
+
+		for _, flags := range []int{0, shwild.ExplicitLeadingPeriod} {
+
+			var expected []string
+
+			for _, name := range names {
+
+				if matched, _ := shwild.Match(pattern, name, shwild.PathMode|shwild.AllowBraceAlternation|flags); matched {
+
+					expected = append(expected, name)
+				}
+			}
+
+			slices.Sort(expected)
+
+			if actual, _ := shwild.Glob(fsys, pattern, shwild.AllowBraceAlternation|flags); !slices.Equal(expected, actual) {
+
+				t.Errorf("Glob('%s') with flags 0x%x returned %q; %q expected", pattern, flags, actual, expected)
+			}
+		}
+	}
+}
+
+func Test_Glob_reads_only_necessary_directories(t *testing.T) {
+
+	fsys := &recording_fs{MapFS: make_test_fs()}
+
+	matches, _ := shwild.Glob(fsys, "src/internal/*.go")
+
+	if expected := []string{"src/internal/util.go"}; !slices.Equal(expected, matches) {
+
+		t.Errorf("Glob() returned %q; %q expected", matches, expected)
+	}
+
+	if expected := []string{"src/internal"}; !slices.Equal(expected, fsys.read) {
+
+		t.Errorf("Glob() read directories %q; %q expected", fsys.read, expected)
+	}
+}
+
+func Test_Glob_with_invalid_pattern(t *testing.T) {
+
+	fsys := make_test_fs()
+
+	if _, err := shwild.Glob(fsys, "src/[abc"); !errors.Is(err, shwild.UnterminatedRange) {
+
+		t.Errorf("Glob() returned %v; %v expected", err, shwild.UnterminatedRange)
+	}
+
+	for _, pattern := range []string{"/src", "src//*.go", "src/"} {
+
+		if _, err := shwild.Glob(fsys, pattern); !errors.Is(err, fs.ErrInvalid) {
+
+			t.Errorf("Glob('%s') returned %v; %v expected", pattern, err, fs.ErrInvalid)
+		}
+	}
+}
+
+func Test_WalkGlob(t *testing.T) {
+
+	fsys := make_test_fs()
+
+	var visited []string
+
+	err := shwild.WalkGlob(fsys, "**/*.go", func(path string, d fs.DirEntry, err error) error {
+
+		if err != nil {
+
+			return err
+		}
+
+		if d.IsDir() {
+
+			t.Errorf("WalkGlob() reported directory '%s'", path)
+		}
+
+		visited = append(visited, path)
+
+		if 3 == len(visited) {
+
+			return fs.SkipAll
+		}
+
+		return nil
+	}, shwild.ExplicitLeadingPeriod)
+
+	if nil != err {
+
+		t.Errorf("WalkGlob() returned %v", err)
+	}
+
+	if expected := []string{"main.go", "src/api.go", "src/api_test.go"}; !slices.Equal(expected, visited) {
+
+		t.Errorf("WalkGlob() visited %q; %q expected", visited, expected)
+	}
+}
+
+func Test_WalkGlob_returns_error_from_fn(t *testing.T) {
+
+	stop := errors.New("stop")
+
+	err := shwild.WalkGlob(make_test_fs(), "*", func(path string, d fs.DirEntry, err error) error {
+
+		return stop
+	})
+
+	if stop != err {
+
+		t.Errorf("WalkGlob() returned %v; %v expected", err, stop)
+	}
+}
+
+func Test_WalkGlob_reports_read_failure_once(t *testing.T) {
+
+	read_err := errors.New("read failed")
+
+	fsys := failing_fs{MapFS: make_test_fs(), dir: "src/internal", err: read_err}
+
+	var failed []string
+
+	err := shwild.WalkGlob(fsys, "**/*.go", func(path string, d fs.DirEntry, err error) error {
+
+		if err != nil {
+
+			failed = append(failed, path)
+		}
+
+		return nil
+	})
+
+	if nil != err || !slices.Equal([]string{"src/internal"}, failed) {
+
+		t.Errorf("WalkGlob() returned %v, having reported failures for %q", err, failed)
+	}
+
+	if _, err := shwild.Glob(fsys, "src/**/*.go"); !errors.Is(err, read_err) {
+
+		t.Errorf("Glob() returned %v; %v expected", err, read_err)
+	}
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
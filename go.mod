@@ -6,10 +6,10 @@ require (
 	github.com/stretchr/testify v1.10.0
 	github.com/synesissoftware/CLASP.Go v0.0.0-20250223051136-3717dd3875f8
 	github.com/synesissoftware/ver2go v0.1.1
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
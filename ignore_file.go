@@ -0,0 +1,88 @@
+// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2026
+// Matthew Wilson and Synesis Information Systems. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+/*
+ * Created: 18th October 2026
+ * Updated: 18th October 2026
+ */
+
+package shwild
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * API functions
+ */
+
+// ReadRuleList reads the lines of an ignore file - such as .gitignore or
+// .dockerignore - from r, and parses them, as does NewRuleList(), into a
+// rule list, subject to additional arguments that moderate behaviour.
+//
+// Lines may end with "\n" or "\r\n", and a leading byte order mark is
+// skipped. A failure to read from r is returned as is, with no rule list;
+// otherwise the errors, if any, are as from NewRuleList().
+func ReadRuleList(r io.Reader, args ...any) (RuleList, error) {
+
+	o, err := parse_options_(args...)
+
+	if nil == err {
+
+		err = o.require_slash_separator()
+	}
+
+	if nil != err {
+
+		return RuleList{}, err
+	}
+
+	flags := o.flags | PathMode
+
+	br := bufio.NewReader(r)
+
+	var rl RuleList
+	var errs []error
+
+	for n := 1; ; n++ {
+
+		line, read_err := br.ReadString('\n')
+
+		if nil != read_err && io.EOF != read_err {
+
+			return RuleList{}, read_err
+		}
+
+		if 0 == len(line) && io.EOF == read_err {
+
+			break
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		line = strings.TrimSuffix(line, "\r")
+
+		if 1 == n {
+
+			line = strings.TrimPrefix(line, "\uFEFF")
+		}
+
+		if err := rl.add_line(n, line, flags); nil != err {
+
+			errs = append(errs, err)
+		}
+
+		if io.EOF == read_err {
+
+			break
+		}
+	}
+
+	return rl, errors.Join(errs...)
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
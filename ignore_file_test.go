@@ -0,0 +1,133 @@
+package shwild_test
+
+import (
+	shwild "github.com/synesissoftware/shwild.Go"
+
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * tests
+ */
+
+func Test_ReadRuleList(t *testing.T) {
+
+	const content = "\uFEFF# generated files\r\n" +
+		"*.log\r\n" +
+		"!important.log\n" +
+		"\n" +
+		"/build/\n" +
+		"\\#hash\n" +
+		"\\!bang\n" +
+		"trailing  \n" +
+		"space\\ \n" +
+		"node_modules/"
+
+	rl, err := shwild.ReadRuleList(strings.NewReader(content))
+	if err != nil {
+
+		t.Fatalf("Failed to read rule list: %v", err)
+	}
+
+	if expected, actual := []int{2, 3, 5, 6, 7, 8, 9, 10}, len(rl.Rules); len(expected) != actual {
+
+		t.Fatalf("rule list has %d rules; %d expected", actual, len(expected))
+	} else {
+
+		for ix, r := range rl.Rules {
+
+			if expected[ix] != r.Line {
+
+				t.Errorf("rule %d ('%s') has line %d; %d expected", ix, r.Pattern, r.Line, expected[ix])
+			}
+		}
+	}
+
+	for _, tc := range []struct {
+		path     string
+		is_dir   bool
+		expected bool
+	}{
+		{"debug.log", false, true},
+		{"important.log", false, false},
+		{"build", true, true},
+		{"build/out.bin", false, true},
+		{"src/build", true, false},
+		{"#hash", false, true},
+		{"!bang", false, true},
+		{"trailing", false, true},
+		{"space ", false, true},
+		{"web/node_modules/x/index.js", false, true},
+		{"# generated files", false, false},
+	} {
+
+		if actual := rl.Match(tc.path, tc.is_dir); tc.expected != actual {
+
+			t.Errorf("Match('%s', %v) returned %v; %v expected", tc.path, tc.is_dir, actual, tc.expected)
+		}
+	}
+}
+
+func Test_ReadRuleList_with_invalid_lines(t *testing.T) {
+
+	rl, err := shwild.ReadRuleList(strings.NewReader("*.log\n[abc\n*.tmp\nx\\\n"))
+
+	if expected, actual := 2, len(rl.Rules); expected != actual {
+
+		t.Errorf("rule list has %d rules; %d expected", actual, expected)
+	}
+
+	var lines []int
+
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+
+		var re *shwild.RuleError
+
+		if !errors.As(e, &re) {
+
+			t.Fatalf("error %v is not a *RuleError", e)
+		}
+
+		lines = append(lines, re.Line)
+	}
+
+	if 2 != len(lines) || 2 != lines[0] || 4 != lines[1] {
+
+		t.Errorf("errors are for lines %v; [2 4] expected", lines)
+	}
+
+	if !errors.Is(err, shwild.UnterminatedRange) || !errors.Is(err, shwild.TrailingEscape) {
+
+		t.Errorf("ReadRuleList() returned %v", err)
+	}
+
+	if prefix := "line 2: invalid pattern '[abc': unterminated range at offset 0"; !strings.HasPrefix(err.Error(), prefix) {
+
+		t.Errorf("error message is '%s'; prefix '%s' expected", err.Error(), prefix)
+	}
+}
+
+func Test_ReadRuleList_with_read_error(t *testing.T) {
+
+	read_err := errors.New("read failed")
+
+	if _, err := shwild.ReadRuleList(iotest.ErrReader(read_err)); read_err != err {
+
+		t.Errorf("ReadRuleList() returned %v; %v expected", err, read_err)
+	}
+}
+
+func Test_ReadRuleList_with_empty_input(t *testing.T) {
+
+	rl, err := shwild.ReadRuleList(strings.NewReader(""))
+
+	if nil != err || 0 != len(rl.Rules) {
+
+		t.Errorf("ReadRuleList() returned (%v, %v)", rl, err)
+	}
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
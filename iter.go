@@ -0,0 +1,105 @@
+// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2026
+// Matthew Wilson and Synesis Information Systems. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+/*
+ * Created: 18th October 2026
+ * Updated: 18th October 2026
+ */
+
+package shwild
+
+import (
+	"io/fs"
+	"iter"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * API types
+ */
+
+// Filter obtains a sequence of the strings of seq that match the pattern,
+// which is evaluated lazily, as it is iterated.
+func (cp CompiledPattern) Filter(seq iter.Seq[string]) iter.Seq[string] {
+
+	return func(yield func(string) bool) {
+
+		for s := range seq {
+
+			if matched, _ := cp.Match(s); matched && !yield(s) {
+
+				return
+			}
+		}
+	}
+}
+
+// FilterIndexed obtains a sequence of the pairs of seq - such as are
+// obtained from slices.All() - whose strings match the pattern, which is
+// evaluated lazily, as it is iterated.
+func (cp CompiledPattern) FilterIndexed(seq iter.Seq2[int, string]) iter.Seq2[int, string] {
+
+	return func(yield func(int, string) bool) {
+
+		for ix, s := range seq {
+
+			if matched, _ := cp.Match(s); matched && !yield(ix, s) {
+
+				return
+			}
+		}
+	}
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * API functions
+ */
+
+// Select obtains a sequence of the strings of seq that match any of the
+// patterns of ps, each paired with the indices of the patterns that it
+// matches (as obtained by PatternSet.Match()), which is evaluated lazily,
+// as it is iterated.
+func Select(ps PatternSet, seq iter.Seq[string]) iter.Seq2[string, []int] {
+
+	return func(yield func(string, []int) bool) {
+
+		for s := range seq {
+
+			if indices := ps.Match(s); nil != indices && !yield(s, indices) {
+
+				return
+			}
+		}
+	}
+}
+
+// GlobSeq obtains a sequence of the names of the entries of fsys that
+// match pattern - as found by WalkGlob() - subject to additional arguments
+// that moderate behaviour, which is evaluated lazily, as it is iterated.
+//
+// A failure to read a directory is yielded as the name of the directory
+// and the error, after which the iteration continues. An invalid pattern
+// is yielded as an empty name and the error, and ends the iteration.
+func GlobSeq(fsys fs.FS, pattern string, args ...any) iter.Seq2[string, error] {
+
+	return func(yield func(string, error) bool) {
+
+		err := WalkGlob(fsys, pattern, func(path string, d fs.DirEntry, err error) error {
+
+			if !yield(path, err) {
+
+				return fs.SkipAll
+			}
+
+			return nil
+		}, args...)
+
+		if nil != err {
+
+			yield("", err)
+		}
+	}
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
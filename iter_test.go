@@ -0,0 +1,167 @@
+package shwild_test
+
+import (
+	shwild "github.com/synesissoftware/shwild.Go"
+
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * tests
+ */
+
+func Test_CompiledPattern_Filter(t *testing.T) {
+
+	cp, _ := shwild.Compile("*.go")
+
+	names := []string{"main.go", "README.md", "api.go", "go.mod"}
+
+	if expected, actual := []string{"main.go", "api.go"}, slices.Collect(cp.Filter(slices.Values(names))); !slices.Equal(expected, actual) {
+
+		t.Errorf("Filter() obtained %q; %q expected", actual, expected)
+	}
+
+	sizes := map[string]int{"main.go": 1, "README.md": 2, "api.go": 3}
+
+	if expected, actual := []string{"api.go", "main.go"}, slices.Sorted(cp.Filter(maps.Keys(sizes))); !slices.Equal(expected, actual) {
+
+		t.Errorf("Filter() obtained %q; %q expected", actual, expected)
+	}
+
+	// stops when the consumer stops
+
+	n := 0
+
+	for range cp.Filter(slices.Values(names)) {
+
+		n++
+
+		break
+	}
+
+	if 1 != n {
+
+		t.Errorf("Filter() yielded %d strings after break", n)
+	}
+}
+
+func Test_CompiledPattern_FilterIndexed(t *testing.T) {
+
+	cp, _ := shwild.Compile("[ab]*")
+
+	var indices []int
+	var values []string
+
+	for ix, s := range cp.FilterIndexed(slices.All([]string{"apple", "cherry", "banana", "date"})) {
+
+		indices = append(indices, ix)
+		values = append(values, s)
+	}
+
+	if !slices.Equal([]int{0, 2}, indices) || !slices.Equal([]string{"apple", "banana"}, values) {
+
+		t.Errorf("FilterIndexed() obtained %v and %q", indices, values)
+	}
+}
+
+func Test_Select(t *testing.T) {
+
+	ps, _ := shwild.CompileSet([]string{"*.go", "*_test.go", "*.md"})
+
+	var selected []string
+	var matches [][]int
+
+	for s, indices := range shwild.Select(ps, slices.Values([]string{"api.go", "api_test.go", "go.mod", "README.md"})) {
+
+		selected = append(selected, s)
+		matches = append(matches, indices)
+	}
+
+	if expected := []string{"api.go", "api_test.go", "README.md"}; !slices.Equal(expected, selected) {
+
+		t.Errorf("Select() obtained %q; %q expected", selected, expected)
+	}
+
+	if expected := [][]int{{0}, {0, 1}, {2}}; !slices.EqualFunc(expected, matches, slices.Equal) {
+
+		t.Errorf("Select() obtained indices %v; %v expected", matches, expected)
+	}
+}
+
+func Test_GlobSeq(t *testing.T) {
+
+	var paths []string
+
+	for path, err := range shwild.GlobSeq(make_test_fs(), "src/**/*.go", shwild.ExplicitLeadingPeriod) {
+
+		if err != nil {
+
+			t.Fatalf("GlobSeq() failed: %v", err)
+		}
+
+		paths = append(paths, path)
+	}
+
+	if expected := []string{"src/api.go", "src/api_test.go", "src/internal/util.go", "src/internal/deep/deep.go"}; !slices.Equal(expected, paths) {
+
+		t.Errorf("GlobSeq() obtained %q; %q expected", paths, expected)
+	}
+
+	// stops when the consumer stops
+
+	n := 0
+
+	for range shwild.GlobSeq(make_test_fs(), "**") {
+
+		n++
+
+		if 2 == n {
+
+			break
+		}
+	}
+
+	if 2 != n {
+
+		t.Errorf("GlobSeq() yielded %d names", n)
+	}
+}
+
+func Test_GlobSeq_with_errors(t *testing.T) {
+
+	for path, err := range shwild.GlobSeq(make_test_fs(), "[abc") {
+
+		if "" != path || !errors.Is(err, shwild.UnterminatedRange) {
+
+			t.Errorf("GlobSeq() yielded ('%s', %v)", path, err)
+		}
+	}
+
+	read_err := errors.New("read failed")
+
+	fsys := failing_fs{MapFS: make_test_fs(), dir: "src/internal", err: read_err}
+
+	var paths []string
+	var failed []string
+
+	for path, err := range shwild.GlobSeq(fsys, "src/**/*.go") {
+
+		if err != nil {
+
+			failed = append(failed, path)
+		} else {
+
+			paths = append(paths, path)
+		}
+	}
+
+	if !slices.Equal([]string{"src/internal"}, failed) || 3 != len(paths) {
+
+		t.Errorf("GlobSeq() obtained %q, and failures for %q", paths, failed)
+	}
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
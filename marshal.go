@@ -0,0 +1,340 @@
+// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2026
+// Matthew Wilson and Synesis Information Systems. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+/*
+ * Created: 18th October 2026
+ * Updated: 18th October 2026
+ */
+
+package shwild
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * constants
+ */
+
+// The versions of the binary form of a compiled pattern: the first is of
+// the flags and the pattern; the second also has the separator.
+const (
+	_BINARY_VERSION           = 1
+	_BINARY_VERSION_SEPARATOR = 2
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal types
+ */
+
+// pattern_object structure
+//
+// The object form of a compiled pattern in JSON and YAML.
+
+type pattern_object struct {
+	Pattern   string   `json:"pattern" yaml:"pattern"`
+	Flags     []string `json:"flags,omitempty" yaml:"flags,omitempty"`
+	Separator string   `json:"separator,omitempty" yaml:"separator,omitempty"`
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * API types
+ */
+
+// Flags obtains the flags with which the pattern was compiled.
+func (cp CompiledPattern) Flags() Flags {
+
+	return Flags(cp.flags)
+}
+
+// Separator obtains the path separator with which the pattern was
+// compiled.
+func (cp CompiledPattern) Separator() rune {
+
+	if 0 == cp.separator {
+
+		return _PATH_SEPARATOR
+	}
+
+	return cp.separator
+}
+
+// MarshalText implements encoding.TextMarshaler, obtaining the pattern. A
+// pattern compiled with any flags, or with a separator other than '/',
+// cannot be represented as text - since they would be lost - and is
+// reported as an error; such a pattern may be marshalled as JSON, as YAML,
+// or in binary.
+func (cp CompiledPattern) MarshalText() ([]byte, error) {
+
+	if 0 != cp.flags {
+
+		return nil, fmt.Errorf("cannot marshal pattern '%s' as text: flags %v cannot be represented", cp.Pattern, Flags(cp.flags))
+	}
+
+	if _PATH_SEPARATOR != cp.Separator() {
+
+		return nil, fmt.Errorf("cannot marshal pattern '%s' as text: separator %q cannot be represented", cp.Pattern, cp.Separator())
+	}
+
+	return []byte(cp.Pattern), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, compiling the text as
+// a pattern with no flags, so that an invalid pattern is reported as a
+// *PatternError.
+func (cp *CompiledPattern) UnmarshalText(text []byte) error {
+
+	return cp.compile(string(text))
+}
+
+// MarshalJSON implements json.Marshaler, obtaining a string if the pattern
+// was compiled with no flags, as in "*.go", and otherwise an object that
+// names the flags, as in {"pattern":"*.go","flags":["IgnoreCase"]}, and
+// which also has the separator if it is other than '/'. A pattern that is
+// not valid UTF-8 cannot be represented in JSON - since each invalid byte
+// would be replaced by U+FFFD - and is reported as an error; such a
+// pattern may be marshalled as YAML, or in binary.
+func (cp CompiledPattern) MarshalJSON() ([]byte, error) {
+
+	if !utf8.ValidString(cp.Pattern) {
+
+		return nil, fmt.Errorf("cannot marshal pattern %q as JSON: it is not valid UTF-8", cp.Pattern)
+	}
+
+	if cp.is_plain() {
+
+		return json.Marshal(cp.Pattern)
+	}
+
+	po, err := cp.make_pattern_object()
+
+	if nil != err {
+
+		return nil, err
+	}
+
+	return json.Marshal(po)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting either of the forms
+// obtained by MarshalJSON(), and compiling the pattern, so that an invalid
+// pattern is reported as a *PatternError, and an unrecognised flag name as
+// a *FlagError. A JSON null leaves the compiled pattern unchanged.
+func (cp *CompiledPattern) UnmarshalJSON(data []byte) error {
+
+	data = bytes.TrimSpace(data)
+
+	if bytes.Equal(data, []byte("null")) {
+
+		return nil
+	}
+
+	if bytes.HasPrefix(data, []byte(`"`)) {
+
+		var pattern string
+
+		if err := json.Unmarshal(data, &pattern); nil != err {
+
+			return err
+		}
+
+		return cp.compile(pattern)
+	}
+
+	var po pattern_object
+
+	if err := json.Unmarshal(data, &po); nil != err {
+
+		return err
+	}
+
+	return cp.compile_object(po)
+}
+
+// MarshalYAML implements the Marshaler interface of the YAML packages -
+// such as gopkg.in/yaml.v3 - obtaining the same forms as MarshalJSON(): a
+// string if the pattern was compiled with no flags, and otherwise a
+// mapping that names the flags and, if it is other than '/', has the
+// separator, as in:
+//
+//	pattern: "*.go"
+//	flags: [IgnoreCase]
+func (cp CompiledPattern) MarshalYAML() (any, error) {
+
+	if cp.is_plain() {
+
+		return cp.Pattern, nil
+	}
+
+	return cp.make_pattern_object()
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of the YAML packages
+// - in the form supported by gopkg.in/yaml.v2, gopkg.in/yaml.v3, and
+// others - accepting either of the forms obtained by MarshalYAML(), and
+// compiling the pattern, as does UnmarshalJSON().
+func (cp *CompiledPattern) UnmarshalYAML(unmarshal func(any) error) error {
+
+	var pattern string
+
+	if err := unmarshal(&pattern); nil == err {
+
+		return cp.compile(pattern)
+	}
+
+	var po pattern_object
+
+	if err := unmarshal(&po); nil != err {
+
+		return err
+	}
+
+	return cp.compile_object(po)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler - and so is used by
+// encoding/gob - obtaining the flags, the separator if it is other than
+// '/', and the pattern.
+func (cp CompiledPattern) MarshalBinary() ([]byte, error) {
+
+	b := []byte{_BINARY_VERSION}
+
+	if _PATH_SEPARATOR != cp.Separator() {
+
+		b[0] = _BINARY_VERSION_SEPARATOR
+	}
+
+	b = binary.AppendUvarint(b, cp.flags)
+
+	if _BINARY_VERSION_SEPARATOR == b[0] {
+
+		b = append(b, byte(cp.Separator()))
+	}
+
+	b = append(b, cp.Pattern...)
+
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, compiling the
+// pattern with the flags and separator obtained by MarshalBinary().
+func (cp *CompiledPattern) UnmarshalBinary(data []byte) error {
+
+	if 0 == len(data) || (_BINARY_VERSION != data[0] && _BINARY_VERSION_SEPARATOR != data[0]) {
+
+		return errors.New("invalid binary form of compiled pattern")
+	}
+
+	flags, n := binary.Uvarint(data[1:])
+
+	if n <= 0 {
+
+		return errors.New("invalid binary form of compiled pattern")
+	}
+
+	args := []any{flags}
+	rest := data[1+n:]
+
+	if _BINARY_VERSION_SEPARATOR == data[0] {
+
+		if 0 == len(rest) {
+
+			return errors.New("invalid binary form of compiled pattern")
+		}
+
+		args = append(args, WithSeparator(rune(rest[0])))
+		rest = rest[1:]
+	}
+
+	return cp.compile(string(rest), args...)
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal functions
+ */
+
+// Determines whether the pattern may be represented by the pattern alone,
+// because it has no flags, and its separator is '/'.
+func (cp CompiledPattern) is_plain() bool {
+
+	return 0 == cp.flags && _PATH_SEPARATOR == cp.Separator()
+}
+
+// Obtains the object form of the pattern, or a *FlagError if it has flags
+// that have no name.
+func (cp CompiledPattern) make_pattern_object() (pattern_object, error) {
+
+	names, unknown := names_of_flags(cp.flags)
+
+	if 0 != unknown {
+
+		return pattern_object{}, &FlagError{Value: unknown}
+	}
+
+	po := pattern_object{Pattern: cp.Pattern, Flags: names}
+
+	if _PATH_SEPARATOR != cp.Separator() {
+
+		po.Separator = string(cp.Separator())
+	}
+
+	return po, nil
+}
+
+// Compiles the object form of a pattern into cp, which is unchanged if the
+// pattern, any of its flag names, or its separator is invalid.
+func (cp *CompiledPattern) compile_object(po pattern_object) error {
+
+	args := make([]any, 0, 1+len(po.Flags))
+
+	for _, name := range po.Flags {
+
+		flag, err := flag_of_name(name)
+
+		if nil != err {
+
+			return err
+		}
+
+		args = append(args, flag)
+	}
+
+	if 0 != len(po.Separator) {
+
+		separator := []rune(po.Separator)
+
+		if 1 != len(separator) {
+
+			return fmt.Errorf("invalid separator %q", po.Separator)
+		}
+
+		args = append(args, WithSeparator(separator[0]))
+	}
+
+	return cp.compile(po.Pattern, args...)
+}
+
+// Compiles the pattern, subject to the arguments, into cp, which is
+// unchanged if the pattern is invalid.
+func (cp *CompiledPattern) compile(pattern string, args ...any) error {
+
+	compiled, err := Compile(pattern, args...)
+
+	if nil != err {
+
+		return err
+	}
+
+	*cp = compiled
+
+	return nil
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
@@ -0,0 +1,444 @@
+package shwild_test
+
+import (
+	shwild "github.com/synesissoftware/shwild.Go"
+	"gopkg.in/yaml.v3"
+
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * tests
+ */
+
+func Test_CompiledPattern_MarshalText(t *testing.T) {
+
+	cp, _ := shwild.Compile("*.go")
+
+	text, err := cp.MarshalText()
+
+	if nil != err || "*.go" != string(text) {
+
+		t.Errorf("MarshalText() obtained ('%s', %v)", text, err)
+	}
+
+	var cp2 shwild.CompiledPattern
+
+	if err := cp2.UnmarshalText(text); nil != err || "*.go" != cp2.Pattern {
+
+		t.Errorf("UnmarshalText() failed: %v", err)
+	}
+
+	if matched, _ := cp2.Match("main.go"); !matched {
+
+		t.Errorf("unmarshalled pattern does not match")
+	}
+
+	cp3, _ := shwild.Compile("*.go", shwild.IgnoreCase)
+
+	if _, err := cp3.MarshalText(); nil == err {
+
+		t.Errorf("MarshalText() succeeded for pattern with flags")
+	}
+
+	if err := cp2.UnmarshalText([]byte("[abc")); !errors.Is(err, shwild.UnterminatedRange) {
+
+		t.Errorf("UnmarshalText() returned %v; %v expected", err, shwild.UnterminatedRange)
+	}
+
+	if "*.go" != cp2.Pattern {
+
+		t.Errorf("failed UnmarshalText() changed pattern to '%s'", cp2.Pattern)
+	}
+}
+
+func Test_CompiledPattern_JSON(t *testing.T) {
+
+	for _, tc := range []struct {
+		pattern  string
+		flags    uint64
+		expected string
+	}{
+		{"*.go", 0, `"*.go"`},
+		{"*.go", shwild.IgnoreCase, `{"pattern":"*.go","flags":["IgnoreCase"]}`},
+		{"src/**/*.go", shwild.PathMode | shwild.ExplicitLeadingPeriod, `{"pattern":"src/**/*.go","flags":["PathMode","ExplicitLeadingPeriod"]}`},
+	} {
+
+		cp, _ := shwild.Compile(tc.pattern, tc.flags)
+
+		data, err := json.Marshal(cp)
+
+		if nil != err || tc.expected != string(data) {
+
+			t.Errorf("json.Marshal() obtained (%s, %v); %s expected", data, err, tc.expected)
+
+			continue
+		}
+
+		var cp2 shwild.CompiledPattern
+
+		if err := json.Unmarshal(data, &cp2); nil != err {
+
+			t.Errorf("json.Unmarshal(%s) failed: %v", data, err)
+
+			continue
+		}
+
+		if tc.pattern != cp2.Pattern || shwild.Flags(tc.flags) != cp2.Flags() {
+
+			t.Errorf("json.Unmarshal(%s) obtained '%s' with flags %v", data, cp2.Pattern, cp2.Flags())
+		}
+	}
+}
+
+func Test_CompiledPattern_JSON_in_config(t *testing.T) {
+
+	var config struct {
+		Include []shwild.CompiledPattern `json:"include"`
+		Exclude *shwild.CompiledPattern  `json:"exclude"`
+	}
+
+	data := `{"include":["*.go",{"pattern":"*.MD","flags":["IgnoreCase"]}],"exclude":null}`
+
+	if err := json.Unmarshal([]byte(data), &config); nil != err {
+
+		t.Fatalf("json.Unmarshal() failed: %v", err)
+	}
+
+	if 2 != len(config.Include) || nil != config.Exclude {
+
+		t.Fatalf("json.Unmarshal() obtained %v", config)
+	}
+
+	if matched, _ := config.Include[1].Match("readme.md"); !matched {
+
+		t.Errorf("unmarshalled pattern '%s' does not match", config.Include[1].Pattern)
+	}
+
+	if err := json.Unmarshal([]byte(`{"include":["src/[abc"]}`), &config); !errors.Is(err, shwild.UnterminatedRange) {
+
+		t.Errorf("json.Unmarshal() returned %v; %v expected", err, shwild.UnterminatedRange)
+	}
+
+	var fe *shwild.FlagError
+
+	if err := json.Unmarshal([]byte(`{"include":[{"pattern":"*","flags":["IgnoreCases"]}]}`), &config); !errors.As(err, &fe) || "IgnoreCases" != fe.Name {
+
+		t.Errorf("json.Unmarshal() returned %v; *FlagError expected", err)
+	}
+}
+
+func Test_CompiledPattern_YAML(t *testing.T) {
+
+	for _, tc := range []struct {
+		pattern  string
+		args     []any
+		expected string
+	}{
+		{"*.go", nil, "'*.go'\n"},
+		{"*.go", []any{shwild.IgnoreCase}, "pattern: '*.go'\nflags:\n    - IgnoreCase\n"},
+		{"src:**:*.go", []any{shwild.WithPathMode(), shwild.WithSeparator(':')}, "pattern: src:**:*.go\nflags:\n    - PathMode\nseparator: ':'\n"},
+	} {
+
+		cp, _ := shwild.Compile(tc.pattern, tc.args...)
+
+		data, err := yaml.Marshal(cp)
+
+		if nil != err || tc.expected != string(data) {
+
+			t.Errorf("yaml.Marshal() obtained (%q, %v); %q expected", data, err, tc.expected)
+
+			continue
+		}
+
+		var cp2 shwild.CompiledPattern
+
+		if err := yaml.Unmarshal(data, &cp2); nil != err {
+
+			t.Errorf("yaml.Unmarshal(%q) failed: %v", data, err)
+
+			continue
+		}
+
+		if cp.Pattern != cp2.Pattern || cp.Flags() != cp2.Flags() || cp.Separator() != cp2.Separator() {
+
+			t.Errorf("yaml.Unmarshal(%q) obtained '%s' with flags %v and separator %q", data, cp2.Pattern, cp2.Flags(), cp2.Separator())
+		}
+	}
+
+}
+
+func Test_CompiledPattern_YAML_in_config(t *testing.T) {
+
+	var config struct {
+		Include []shwild.CompiledPattern `yaml:"include"`
+		Exclude *shwild.CompiledPattern  `yaml:"exclude"`
+	}
+
+	data := `
+include:
+  - "*.go"
+  - pattern: "*.MD"
+    flags: [IgnoreCase]
+`
+
+	if err := yaml.Unmarshal([]byte(data), &config); nil != err {
+
+		t.Fatalf("yaml.Unmarshal() failed: %v", err)
+	}
+
+	if 2 != len(config.Include) || nil != config.Exclude {
+
+		t.Fatalf("yaml.Unmarshal() obtained %v", config)
+	}
+
+	if matched, _ := config.Include[1].Match("readme.md"); !matched {
+
+		t.Errorf("unmarshalled pattern '%s' does not match", config.Include[1].Pattern)
+	}
+
+	if err := yaml.Unmarshal([]byte(`include: ["src/[abc"]`), &config); !errors.Is(err, shwild.UnterminatedRange) {
+
+		t.Errorf("yaml.Unmarshal() returned %v; %v expected", err, shwild.UnterminatedRange)
+	}
+
+	var fe *shwild.FlagError
+
+	if err := yaml.Unmarshal([]byte(`include: [{pattern: "*", flags: [IgnoreCases]}]`), &config); !errors.As(err, &fe) || "IgnoreCases" != fe.Name {
+
+		t.Errorf("yaml.Unmarshal() returned %v; *FlagError expected", err)
+	}
+}
+
+func Test_CompiledPattern_zero_value(t *testing.T) {
+
+	var config struct {
+		Include shwild.CompiledPattern `json:"include"`
+		Exclude shwild.CompiledPattern `json:"exclude"`
+	}
+
+	if err := json.Unmarshal([]byte(`{"exclude":null}`), &config); nil != err {
+
+		t.Fatalf("json.Unmarshal() failed: %v", err)
+	}
+
+	// the zero value is the empty pattern, which matches only the empty
+	// string
+
+	empty, _ := shwild.Compile("")
+
+	for _, cp := range []shwild.CompiledPattern{config.Include, config.Exclude} {
+
+		if matched, err := cp.Match(""); !matched || nil != err {
+
+			t.Errorf("Match('') obtained (%v, %v)", matched, err)
+		}
+
+		if matched, _ := cp.Match("abc"); matched {
+
+			t.Errorf("Match('abc') matched")
+		}
+
+		if matched, _ := cp.MatchBytes([]byte("abc")); matched {
+
+			t.Errorf("MatchBytes() matched")
+		}
+
+		if matched, _ := cp.MatchRunes([]rune("abc")); matched {
+
+			t.Errorf("MatchRunes() matched")
+		}
+
+		if matched, _ := cp.MatchReader(bytes.NewReader(nil)); !matched {
+
+			t.Errorf("MatchReader() did not match")
+		}
+
+		if _, ok := cp.FindSubmatch("abc"); ok {
+
+			t.Errorf("FindSubmatch() matched")
+		}
+
+		if _, ok := cp.FindSubmatchIndex(""); !ok {
+
+			t.Errorf("FindSubmatchIndex() did not match")
+		}
+
+		if shwild.NoMatch != cp.MatchPartial("a") || !cp.MatchPrefix("") {
+
+			t.Errorf("MatchPartial() or MatchPrefix() obtained an unexpected result")
+		}
+
+		if start, end, ok := cp.Find("abc"); !ok || 0 != start || 0 != end {
+
+			t.Errorf("Find() obtained (%d, %d, %v)", start, end, ok)
+		}
+
+		if expected, actual := empty.FindAll("abc", -1), cp.FindAll("abc", -1); !slices.EqualFunc(expected, actual, slices.Equal) {
+
+			t.Errorf("FindAll() obtained %v; %v expected", actual, expected)
+		}
+
+		if !cp.Contains("abc") {
+
+			t.Errorf("Contains() did not match")
+		}
+
+		if _, ok, err := cp.Replace("abc", "x"); ok || nil != err {
+
+			t.Errorf("Replace() obtained (%v, %v)", ok, err)
+		}
+
+		if data, err := json.Marshal(cp); nil != err || `""` != string(data) {
+
+			t.Errorf("json.Marshal() obtained (%s, %v)", data, err)
+		}
+
+		if expected, actual := empty.String(), cp.String(); expected != actual {
+
+			t.Errorf("String() obtained '%s'; '%s' expected", actual, expected)
+		}
+	}
+}
+
+func Test_CompiledPattern_gob(t *testing.T) {
+
+	cp, _ := shwild.Compile("*.{go,md}", shwild.AllowBraceAlternation|shwild.IgnoreCase)
+
+	var buf bytes.Buffer
+
+	if err := gob.NewEncoder(&buf).Encode(cp); nil != err {
+
+		t.Fatalf("Encode() failed: %v", err)
+	}
+
+	var cp2 shwild.CompiledPattern
+
+	if err := gob.NewDecoder(&buf).Decode(&cp2); nil != err {
+
+		t.Fatalf("Decode() failed: %v", err)
+	}
+
+	if cp.Pattern != cp2.Pattern || cp.Flags() != cp2.Flags() {
+
+		t.Errorf("Decode() obtained '%s' with flags %v", cp2.Pattern, cp2.Flags())
+	}
+
+	if matched, _ := cp2.Match("README.MD"); !matched {
+
+		t.Errorf("decoded pattern does not match")
+	}
+
+	for _, data := range [][]byte{nil, {0}, {1, 0x80}} {
+
+		if err := cp2.UnmarshalBinary(data); nil == err {
+
+			t.Errorf("UnmarshalBinary(%v) succeeded", data)
+		}
+	}
+}
+
+func Test_CompiledPattern_marshal_separator(t *testing.T) {
+
+	cp, _ := shwild.Compile("src:**:*.go", shwild.WithPathMode(), shwild.WithSeparator(':'))
+
+	if _, err := cp.MarshalText(); nil == err {
+
+		t.Errorf("MarshalText() succeeded for pattern with separator")
+	}
+
+	data, err := json.Marshal(cp)
+
+	if expected := `{"pattern":"src:**:*.go","flags":["PathMode"],"separator":":"}`; nil != err || expected != string(data) {
+
+		t.Errorf("json.Marshal() obtained (%s, %v); %s expected", data, err, expected)
+	}
+
+	var cp2 shwild.CompiledPattern
+
+	if err := json.Unmarshal(data, &cp2); nil != err || ':' != cp2.Separator() {
+
+		t.Errorf("json.Unmarshal(%s) obtained separator %q, and %v", data, cp2.Separator(), err)
+	}
+
+	b, _ := cp.MarshalBinary()
+
+	var cp3 shwild.CompiledPattern
+
+	if err := cp3.UnmarshalBinary(b); nil != err || ':' != cp3.Separator() || shwild.PathMode != cp3.Flags() {
+
+		t.Errorf("UnmarshalBinary() obtained separator %q and flags %v, and %v", cp3.Separator(), cp3.Flags(), err)
+	}
+
+	for _, cp := range []shwild.CompiledPattern{cp2, cp3} {
+
+		if matched, _ := cp.Match("src:a:b:main.go"); !matched {
+
+			t.Errorf("unmarshalled pattern does not match")
+		}
+	}
+}
+
+func Test_CompiledPattern_marshal_invalid_UTF8(t *testing.T) {
+
+	for _, args := range [][]any{nil, {shwild.IgnoreCase}} {
+
+		cp, err := shwild.Compile("\xffa{-", args...)
+
+		if nil != err {
+
+			t.Fatalf("Compile() failed: %v", err)
+		}
+
+		// JSON would replace the invalid byte, and so obtain a different
+		// pattern
+
+		if data, err := json.Marshal(cp); nil == err {
+
+			t.Errorf("json.Marshal() obtained %s", data)
+		}
+
+		data, err := yaml.Marshal(cp)
+
+		if nil != err {
+
+			t.Fatalf("yaml.Marshal() failed: %v", err)
+		}
+
+		var cp2 shwild.CompiledPattern
+
+		if err := yaml.Unmarshal(data, &cp2); nil != err || cp.Pattern != cp2.Pattern || cp.Flags() != cp2.Flags() {
+
+			t.Errorf("yaml.Unmarshal(%q) obtained %q with flags %v, and %v", data, cp2.Pattern, cp2.Flags(), err)
+		}
+
+		b, _ := cp.MarshalBinary()
+
+		var cp3 shwild.CompiledPattern
+
+		if err := cp3.UnmarshalBinary(b); nil != err || cp.Pattern != cp3.Pattern || cp.Flags() != cp3.Flags() {
+
+			t.Errorf("UnmarshalBinary() obtained %q with flags %v, and %v", cp3.Pattern, cp3.Flags(), err)
+		}
+
+		for _, unmarshalled := range []shwild.CompiledPattern{cp2, cp3} {
+
+			if matched, _ := unmarshalled.Match("\xffa{-"); !matched {
+
+				t.Errorf("unmarshalled pattern does not match")
+			}
+
+			if matched, _ := unmarshalled.Match("\ufffda{-"); matched {
+
+				t.Errorf("unmarshalled pattern matches U+FFFD")
+			}
+		}
+	}
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
@@ -3,9 +3,11 @@ package shwild_test
 import (
 	shwild "github.com/synesissoftware/shwild.Go"
 
+	"errors"
 	"fmt"
 	"path"
 	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -13,9 +15,9 @@ import (
  * internal functions
  */
 
-func check_Match(t *testing.T, pattern, s string, expectedResult bool, e error) {
+func check_Match(t *testing.T, pattern, s string, expectedResult bool, e error, args ...any) {
 
-	m_r, m_e := shwild.Match(pattern, s)
+	m_r, m_e := shwild.Match(pattern, s, args...)
 
 	if expectedResult == m_r && e == m_e {
 
@@ -41,6 +43,27 @@ func check_Match(t *testing.T, pattern, s string, expectedResult bool, e error)
 	t.Fail()
 }
 
+func check_Match_PatternError(t *testing.T, pattern, s string, expectedKind shwild.PatternErrorKind, expectedOffset int, args ...any) {
+
+	m_r, m_e := shwild.Match(pattern, s, args...)
+
+	var pe *shwild.PatternError
+
+	if !m_r && errors.As(m_e, &pe) && expectedKind == pe.Kind && expectedOffset == pe.Offset && pattern == pe.Pattern {
+
+		return
+	}
+
+	_, file, line, hasCallInfo := runtime.Caller(1)
+
+	if hasCallInfo {
+
+		fmt.Printf("\t%s:%d: Match('%s', '%s') returned '%v', '%v'; %v at offset %d expected\n", path.Base(file), line, pattern, s, m_r, m_e, expectedKind, expectedOffset)
+	}
+
+	t.Fail()
+}
+
 /* /////////////////////////////////////////////////////////////////////////
  * tests
  */
@@ -242,6 +265,9 @@ func Test_Match_with_escaped_special_characters(t *testing.T) {
 	check_Match(t, "a\\[c", "a_c", false, nil)
 	check_Match(t, "a\\[c", "a[c", true, nil)
 
+	check_Match(t, "[ab]\\*", "a*", true, nil)
+	check_Match(t, "[ab]\\*", "a", false, nil)
+
 	check_Match(t, "a\\]c", "a_c", false, nil)
 	check_Match(t, "a\\]c", "a]c", true, nil)
 	check_Match(t, "a]c", "a]c", true, nil)
@@ -259,4 +285,740 @@ func Test_Pattern_Match_from_examples_1(t *testing.T) {
 	check_Match(t, pattern, "LICENSE", false, nil)
 }
 
+func Test_Match_with_multibyte_characters(t *testing.T) {
+
+	check_Match(t, "?", "\u00e9", true, nil)
+	check_Match(t, "??", "\u00e9", false, nil)
+	check_Match(t, "?", "\U0001F600", true, nil)
+	check_Match(t, "caf?", "caf\u00e9", true, nil)
+	check_Match(t, "caf?", "cafe\u0301", false, nil)
+	check_Match(t, "caf??", "cafe\u0301", true, nil)
+	check_Match(t, "*?[^x]?", "\u00e9\u00e9", false, nil)
+	check_Match(t, "*\u00e9", "caf\u00e9", true, nil)
+	check_Match(t, "\u65e5*\u8a9e", "\u65e5\u672c\u8a9e", true, nil)
+
+	check_Match(t, "[\u00e9]", "\u00e9", true, nil)
+	check_Match(t, "[\u00e9]", "e", false, nil)
+	check_Match(t, "[e\u00e9\u00e8]", "\u00e8", true, nil)
+	check_Match(t, "[^\u00e9]", "\u00e9", false, nil)
+	check_Match(t, "[^\u00e9]", "\u00e8", true, nil)
+	check_Match(t, "[\u00e0-\u00e9]", "\u00e4", true, nil)
+	check_Match(t, "[\u00e0-\u00e9]", "\u00ea", false, nil)
+	check_Match(t, "[\u03b1-\u03c9]*", "\u03bb\u03cc\u03b3\u03bf\u03c2", true, nil)
+	check_Match(t, "[\u03b1-\u03a9]", "\u0394", true, nil)
+	check_Match(t, "[^\u03b1-\u03c9]", "\u03bb", false, nil)
+}
+
+func Test_Match_with_invalid_UTF8(t *testing.T) {
+
+	// an invalid byte is a single character, matched only by '?', '*',
+	// not-ranges, and identical literal bytes
+
+	check_Match(t, "?", "\xff", true, nil)
+	check_Match(t, "??", "\xff", false, nil)
+	check_Match(t, "a?c", "a\xffc", true, nil)
+	check_Match(t, "*", "\xff\xfe", true, nil)
+	check_Match(t, "*c", "\xff\xfec", true, nil)
+	check_Match(t, "[^a]", "\xff", true, nil)
+	check_Match(t, "[\ufffd]", "\xff", false, nil)
+	check_Match(t, "[\ufffd]", "\ufffd", true, nil)
+	check_Match(t, "a\xff", "a\xff", true, nil)
+	check_Match(t, "a\xff", "a\ufffd", false, nil)
+	check_Match(t, "a\xff", "A\xff", true, nil, shwild.IgnoreCase)
+	check_Match(t, "a\xff", "A\xfe", false, nil, shwild.IgnoreCase)
+}
+
+func Test_Match_with_unterminated_range(t *testing.T) {
+
+	check_Match_PatternError(t, "[", "", shwild.UnterminatedRange, 0)
+	check_Match_PatternError(t, "[abc", "a", shwild.UnterminatedRange, 0)
+	check_Match_PatternError(t, "[^abc", "d", shwild.UnterminatedRange, 0)
+	check_Match_PatternError(t, "ab[cd", "abc", shwild.UnterminatedRange, 2)
+	check_Match_PatternError(t, "[ab]*[cd", "abc", shwild.UnterminatedRange, 5)
+	check_Match_PatternError(t, "[]abc", "a", shwild.UnterminatedRange, 0)
+	check_Match_PatternError(t, "[]abc", "a", shwild.UnterminatedRange, 0, shwild.AllowRangeLiteralBracket)
+
+	check_Match(t, "[abc", "[abc", true, nil, shwild.SuppressRangeSupport)
+}
+
+func Test_Match_with_empty_range(t *testing.T) {
+
+	check_Match_PatternError(t, "[]", "", shwild.EmptyRange, 0)
+	check_Match_PatternError(t, "[^]", "", shwild.EmptyRange, 0)
+	check_Match_PatternError(t, "a*[]", "a", shwild.EmptyRange, 2)
+
+	check_Match_PatternError(t, "[]", "", shwild.EmptyRange, 0, shwild.AllowRangeLiteralBracket)
+	check_Match_PatternError(t, "[^]", "", shwild.EmptyRange, 0, shwild.AllowRangeLiteralBracket)
+}
+
+func Test_Match_with_AllowRangeLiteralBracket(t *testing.T) {
+
+	// without the flag, ] is literal only when first, and [ is literal
+
+	check_Match(t, "[]]", "]", true, nil)
+	check_Match(t, "[^]]", "a", true, nil)
+	check_Match(t, "[]abc]", "]", true, nil)
+	check_Match(t, "[]abc]", "b", true, nil)
+	check_Match(t, "[a[]", "[", true, nil)
+	check_Match(t, "[a[]", "a", true, nil)
+	check_Match(t, "[a]]", "a]", true, nil)
+	check_Match(t, "[a]]", "]", false, nil)
+
+	// with the flag, ] is literal when first ...
+
+	check_Match(t, "[]]", "]", true, nil, shwild.AllowRangeLiteralBracket)
+	check_Match(t, "[]abc]", "]", true, nil, shwild.AllowRangeLiteralBracket)
+	check_Match(t, "[]abc]", "b", true, nil, shwild.AllowRangeLiteralBracket)
+	check_Match(t, "[]abc]", "d", false, nil, shwild.AllowRangeLiteralBracket)
+	check_Match(t, "[^]abc]", "]", false, nil, shwild.AllowRangeLiteralBracket)
+	check_Match(t, "[^]abc]", "d", true, nil, shwild.AllowRangeLiteralBracket)
+
+	// ... or immediately before the closing ]
+
+	check_Match(t, "[a]]", "]", true, nil, shwild.AllowRangeLiteralBracket)
+	check_Match(t, "[a]]", "a", true, nil, shwild.AllowRangeLiteralBracket)
+	check_Match(t, "[a]]", "a]", false, nil, shwild.AllowRangeLiteralBracket)
+	check_Match(t, "[a]b]", "ab]", true, nil, shwild.AllowRangeLiteralBracket)
+	check_Match(t, "[a]b]", "]", false, nil, shwild.AllowRangeLiteralBracket)
+
+	// [ is literal
+
+	check_Match(t, "[a[]", "[", true, nil, shwild.AllowRangeLiteralBracket)
+	check_Match(t, "[a[]", "a", true, nil, shwild.AllowRangeLiteralBracket)
+	check_Match(t, "[[]", "[", true, nil, shwild.AllowRangeLiteralBracket)
+	check_Match(t, "[][]", "[", true, nil, shwild.AllowRangeLiteralBracket)
+	check_Match(t, "[][]", "]", true, nil, shwild.AllowRangeLiteralBracket)
+	check_Match(t, "*[][]*.log", "app[2].log", true, nil, shwild.AllowRangeLiteralBracket)
+	check_Match(t, "*[][]*.log", "app-2.log", false, nil, shwild.AllowRangeLiteralBracket)
+}
+
+func Test_Match_with_trailing_escape(t *testing.T) {
+
+	check_Match_PatternError(t, "\\", "", shwild.TrailingEscape, 0)
+	check_Match_PatternError(t, "abc\\", "abc", shwild.TrailingEscape, 3)
+	check_Match_PatternError(t, "a\\\\\\", "a\\", shwild.TrailingEscape, 3)
+
+	check_Match(t, "a\\\\", "a\\", true, nil)
+	check_Match(t, "abc\\", "abc\\", true, nil, shwild.SuppressBackslashEscape)
+}
+
+func Test_Match_with_invalid_continuum(t *testing.T) {
+
+	check_Match_PatternError(t, "[a-c-e]", "b", shwild.InvalidContinuum, 4)
+	check_Match_PatternError(t, "[^0-5-9]", "b", shwild.InvalidContinuum, 5)
+
+	check_Match(t, "[a-c-]", "-", true, nil)
+	check_Match(t, "[a-ce-g]", "f", true, nil)
+	check_Match(t, "[a-c-e]", "-", true, nil, shwild.SuppressRangeContinuumSupport)
+	check_Match(t, "[c-a-e]", "d", true, nil, shwild.SuppressRangeContinuumHighlowSupport)
+}
+
+func Test_Match_PatternError(t *testing.T) {
+
+	_, err := shwild.Match("\u00e9t\u00e9[abc", "")
+
+	if !errors.Is(err, shwild.UnterminatedRange) {
+
+		t.Errorf("errors.Is(%v, UnterminatedRange) returned false", err)
+	}
+
+	if errors.Is(err, shwild.EmptyRange) {
+
+		t.Errorf("errors.Is(%v, EmptyRange) returned true", err)
+	}
+
+	var pe *shwild.PatternError
+
+	if !errors.As(err, &pe) {
+
+		t.Fatalf("errors.As(%v, *PatternError) returned false", err)
+	}
+
+	if 5 != pe.Offset {
+
+		t.Errorf("PatternError.Offset is %d; %d expected", pe.Offset, 5)
+	}
+
+	if 3 != pe.RuneOffset {
+
+		t.Errorf("PatternError.RuneOffset is %d; %d expected", pe.RuneOffset, 3)
+	}
+
+	if expected := "invalid pattern '\u00e9t\u00e9[abc': unterminated range at offset 3"; expected != err.Error() {
+
+		t.Errorf("PatternError.Error() returned %q; %q expected", err.Error(), expected)
+	}
+}
+
+func Test_Match_with_SuppressRangeSupport(t *testing.T) {
+
+	check_Match(t, "[abc]", "a", true, nil)
+	check_Match(t, "[abc]", "[abc]", false, nil)
+
+	check_Match(t, "[abc]", "a", false, nil, shwild.SuppressRangeSupport)
+	check_Match(t, "[abc]", "[abc]", true, nil, shwild.SuppressRangeSupport)
+	check_Match(t, "a[*]", "a[bc]", true, nil, shwild.SuppressRangeSupport)
+	check_Match(t, "a]", "a]", true, nil, shwild.SuppressRangeSupport)
+}
+
+func Test_Match_with_SuppressBackslashEscape(t *testing.T) {
+
+	check_Match(t, "a\\*c", "a*c", true, nil)
+	check_Match(t, "a\\*c", "a\\*c", false, nil)
+
+	check_Match(t, "a\\*c", "a*c", false, nil, shwild.SuppressBackslashEscape)
+	check_Match(t, "a\\*c", "a\\c", true, nil, shwild.SuppressBackslashEscape)
+	check_Match(t, "a\\*c", "a\\bc", true, nil, shwild.SuppressBackslashEscape)
+	check_Match(t, "a\\", "a\\", true, nil, shwild.SuppressBackslashEscape)
+}
+
+func Test_Match_with_SuppressRangeContinuumSupport(t *testing.T) {
+
+	check_Match(t, "[a-c]", "b", true, nil)
+	check_Match(t, "[a-c]", "-", false, nil)
+
+	check_Match(t, "[a-c]", "a", true, nil, shwild.SuppressRangeContinuumSupport)
+	check_Match(t, "[a-c]", "b", false, nil, shwild.SuppressRangeContinuumSupport)
+	check_Match(t, "[a-c]", "c", true, nil, shwild.SuppressRangeContinuumSupport)
+	check_Match(t, "[a-c]", "-", true, nil, shwild.SuppressRangeContinuumSupport)
+	check_Match(t, "[^a-c]", "b", true, nil, shwild.SuppressRangeContinuumSupport)
+	check_Match(t, "[^a-c]", "-", false, nil, shwild.SuppressRangeContinuumSupport)
+}
+
+func Test_Match_with_SuppressRangeContinuumHighlowSupport(t *testing.T) {
+
+	check_Match(t, "[c-a]", "b", true, nil)
+
+	check_Match(t, "[c-a]", "a", true, nil, shwild.SuppressRangeContinuumHighlowSupport)
+	check_Match(t, "[c-a]", "b", false, nil, shwild.SuppressRangeContinuumHighlowSupport)
+	check_Match(t, "[c-a]", "c", true, nil, shwild.SuppressRangeContinuumHighlowSupport)
+	check_Match(t, "[c-a]", "-", true, nil, shwild.SuppressRangeContinuumHighlowSupport)
+	check_Match(t, "[a-c]", "b", true, nil, shwild.SuppressRangeContinuumHighlowSupport)
+	check_Match(t, "[a-c]", "-", false, nil, shwild.SuppressRangeContinuumHighlowSupport)
+	check_Match(t, "[C-a]", "b", false, nil, shwild.SuppressRangeContinuumHighlowSupport)
+	check_Match(t, "[a-C]", "b", true, nil, shwild.SuppressRangeContinuumHighlowSupport)
+}
+
+func Test_Match_with_SuppressRangeContinuumCrosscaseSupport(t *testing.T) {
+
+	check_Match(t, "[a-C]", "B", true, nil)
+	check_Match(t, "[a-C]", "_", false, nil)
+
+	// without cross-case support, the continuum is ordinal, i.e. [C-a]
+
+	check_Match(t, "[a-C]", "a", true, nil, shwild.SuppressRangeContinuumCrosscaseSupport)
+	check_Match(t, "[a-C]", "b", false, nil, shwild.SuppressRangeContinuumCrosscaseSupport)
+	check_Match(t, "[a-C]", "B", false, nil, shwild.SuppressRangeContinuumCrosscaseSupport)
+	check_Match(t, "[a-C]", "C", true, nil, shwild.SuppressRangeContinuumCrosscaseSupport)
+	check_Match(t, "[a-C]", "Z", true, nil, shwild.SuppressRangeContinuumCrosscaseSupport)
+	check_Match(t, "[a-C]", "_", true, nil, shwild.SuppressRangeContinuumCrosscaseSupport)
+}
+
+func Test_Match_with_SuppressRangeLiteralWildcard(t *testing.T) {
+
+	check_Match(t, "[*?]", "*", true, nil)
+	check_Match(t, "[*?]", "?", true, nil)
+	check_Match(t, "[*?]", "a", false, nil)
+
+	check_Match_PatternError(t, "[*?]", "*", shwild.WildcardInRange, 1, shwild.SuppressRangeLiteralWildcard)
+	check_Match_PatternError(t, "[a*]", "a", shwild.WildcardInRange, 2, shwild.SuppressRangeLiteralWildcard)
+	check_Match_PatternError(t, "[^?]", "a", shwild.WildcardInRange, 2, shwild.SuppressRangeLiteralWildcard)
+	check_Match(t, "[ab]*", "abc", true, nil, shwild.SuppressRangeLiteralWildcard)
+}
+
+func Test_Match_with_SuppressRangeLeadtrailLiteralHyphen(t *testing.T) {
+
+	check_Match(t, "[-a]", "-", true, nil)
+	check_Match(t, "[a-]", "-", true, nil)
+
+	check_Match_PatternError(t, "[-a]", "-", shwild.LeadtrailHyphenInRange, 1, shwild.SuppressRangeLeadtrailLiteralHyphen)
+	check_Match_PatternError(t, "[a-]", "-", shwild.LeadtrailHyphenInRange, 2, shwild.SuppressRangeLeadtrailLiteralHyphen)
+	check_Match_PatternError(t, "[^-a]", "b", shwild.LeadtrailHyphenInRange, 2, shwild.SuppressRangeLeadtrailLiteralHyphen)
+	check_Match(t, "[a-c]", "b", true, nil, shwild.SuppressRangeLeadtrailLiteralHyphen)
+
+	check_Match(t, "[-a]", "-", true, nil, shwild.SuppressRangeLeadtrailLiteralHyphen, shwild.SuppressRangeContinuumSupport)
+}
+
+func Test_Match_with_SuppressRangeNot(t *testing.T) {
+
+	check_Match(t, "[^a]", "b", true, nil)
+	check_Match(t, "[^a]", "^", true, nil)
+
+	check_Match(t, "[^a]", "a", true, nil, shwild.SuppressRangeNot)
+	check_Match(t, "[^a]", "^", true, nil, shwild.SuppressRangeNot)
+	check_Match(t, "[^a]", "b", false, nil, shwild.SuppressRangeNot)
+}
+
+func Test_Match_with_IgnoreCase(t *testing.T) {
+
+	check_Match(t, "abc", "ABC", false, nil)
+	check_Match(t, "[abc]", "B", false, nil)
+
+	check_Match(t, "abc", "ABC", true, nil, shwild.IgnoreCase)
+	check_Match(t, "abc", "aBc", true, nil, shwild.IgnoreCase)
+	check_Match(t, "ABC", "abc", true, nil, shwild.IgnoreCase)
+	check_Match(t, "abc", "abd", false, nil, shwild.IgnoreCase)
+	check_Match(t, "*.JPG", "photo.jpg", true, nil, shwild.IgnoreCase)
+	check_Match(t, "[abc]", "B", true, nil, shwild.IgnoreCase)
+	check_Match(t, "[a-c]", "C", true, nil, shwild.IgnoreCase)
+	check_Match(t, "[a-c]", "D", false, nil, shwild.IgnoreCase)
+	check_Match(t, "[^abc]", "B", false, nil, shwild.IgnoreCase)
+	check_Match(t, "[^abc]", "D", true, nil, shwild.IgnoreCase)
+}
+
+func Test_Match_with_IgnoreCase_and_Unicode_case_folding(t *testing.T) {
+
+	// sharp-s (U+00DF) and capital sharp-s (U+1E9E)
+
+	check_Match(t, "stra\u00dfe", "STRA\u1E9EE", false, nil)
+	check_Match(t, "stra\u00dfe", "STRA\u1E9EE", true, nil, shwild.IgnoreCase)
+	check_Match(t, "stra\u00dfe", "STRASSE", false, nil, shwild.IgnoreCase)
+
+	// sigma (U+03C3), final sigma (U+03C2), capital sigma (U+03A3)
+
+	check_Match(t, "\u03c3\u03c3", "\u03a3\u03c2", false, nil)
+	check_Match(t, "\u03c3\u03c3", "\u03a3\u03c2", true, nil, shwild.IgnoreCase)
+	check_Match(t, "\u03c2*", "\u03a3", true, nil, shwild.IgnoreCase)
+
+	// Turkish dotted/dotless I have no simple case-folding equivalents
+
+	check_Match(t, "\u0130", "i", false, nil, shwild.IgnoreCase)
+	check_Match(t, "\u0130", "I", false, nil, shwild.IgnoreCase)
+	check_Match(t, "\u0131", "I", false, nil, shwild.IgnoreCase)
+	check_Match(t, "\u0130", "\u0130", true, nil, shwild.IgnoreCase)
+
+	// KELVIN SIGN (U+212A) has a different encoded length to 'k'
+
+	check_Match(t, "\u212a?", "kx", true, nil, shwild.IgnoreCase)
+	check_Match(t, "k?", "\u212ax", true, nil, shwild.IgnoreCase)
+	check_Match(t, "k?", "\u212a", false, nil, shwild.IgnoreCase)
+	check_Match(t, "*K.TXT", "file-\u212a.txt", true, nil, shwild.IgnoreCase)
+}
+
+func Test_Match_with_IgnoreCase_and_ranges(t *testing.T) {
+
+	check_Match(t, "[A-C]", "b", true, nil, shwild.IgnoreCase)
+	check_Match(t, "[a-c]", "B", true, nil, shwild.IgnoreCase)
+	check_Match(t, "[c-a]", "B", true, nil, shwild.IgnoreCase)
+	check_Match(t, "[a-C]", "d", false, nil, shwild.IgnoreCase)
+	check_Match(t, "[a-C]", "c", true, nil, shwild.IgnoreCase)
+	check_Match(t, "[^A-C]", "b", false, nil, shwild.IgnoreCase)
+	check_Match(t, "[^A-C]", "d", true, nil, shwild.IgnoreCase)
+	check_Match(t, "[\u212a]", "k", true, nil, shwild.IgnoreCase)
+	check_Match(t, "[\u212a]", "K", true, nil, shwild.IgnoreCase)
+	check_Match(t, "[^\u212a]", "k", false, nil, shwild.IgnoreCase)
+	check_Match(t, "[0-9]", "0", true, nil, shwild.IgnoreCase)
+	check_Match(t, "[k]", "\u212a", true, nil, shwild.IgnoreCase)
+	check_Match(t, "[\u03c3]", "\u03a3", true, nil, shwild.IgnoreCase)
+	check_Match(t, "[^\u03c3]", "\u03c2", false, nil, shwild.IgnoreCase)
+	check_Match(t, "[\u00e0-\u00e9]", "\u00c4", true, nil, shwild.IgnoreCase)
+}
+
+func Test_Match_with_wide_ranges(t *testing.T) {
+
+	check_Match(t, "[\u0000-\U0010ffff]", "a", true, nil)
+	check_Match(t, "[\u0000-\U0010ffff]", "\U0010ffff", true, nil)
+	check_Match(t, "[\u0000-\U0010ffff]", "\xff", false, nil)
+	check_Match(t, "[^\u0000-\U0010ffff]", "a", false, nil)
+	check_Match(t, "[^\u0000-\U0010ffff]", "\xff", true, nil)
+	check_Match(t, "[\u4e00-\u9fff]*", "\u6f22\u5b57", true, nil)
+	check_Match(t, "[\u4e00-\u9fff]*", "\u3042", false, nil)
+	check_Match(t, "[\U0010ffff-\u0080]", "\u00e9", true, nil)
+	check_Match(t, "[\U0010ffff-\u0080]", "e", false, nil)
+	check_Match(t, "[\u0100-\U0010ffff]", "\u00e9", false, nil)
+	check_Match(t, "[\u0100-\U0010ffff]", "\u00c9", false, nil, shwild.IgnoreCase)
+	check_Match(t, "[\u0100-\U0010ffff]", "\u212a", true, nil, shwild.IgnoreCase)
+}
+
+func Test_Match_with_AllowRangeQuantification(t *testing.T) {
+
+	// without the flag, an escaped digit is literal
+
+	check_Match(t, "[0-9]\\4", "54", true, nil)
+	check_Match(t, "[0-9]\\4", "5555", false, nil)
+
+	// exact counts
+
+	check_Match(t, "[0-9]\\4", "2024", true, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "[0-9]\\4", "202", false, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "[0-9]\\4", "20245", false, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "[0-9]\\4", "20a4", false, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "[^0-9]\\2", "ab", true, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "[^0-9]\\2", "a1", false, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "?\\3", "abc", true, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "?\\3", "ab", false, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "ba\\3", "baaa", true, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "ba\\3", "bababa", false, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "\\*\\2", "**", true, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "\u00e9\\2", "\u00e9\u00e9", true, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "a\\0b", "b", true, nil, shwild.AllowRangeQuantification)
+
+	// bounded and unbounded counts
+
+	check_Match(t, "v[0-9]\\1-3.txt", "v.txt", false, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "v[0-9]\\1-3.txt", "v1.txt", true, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "v[0-9]\\1-3.txt", "v123.txt", true, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "v[0-9]\\1-3.txt", "v1234.txt", false, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "ba\\2-", "ba", false, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "ba\\2-", "baa", true, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "ba\\2-", "baaaaaaaa", true, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "x\\-2", "", true, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "x\\-2", "xx", true, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "x\\-2", "xxx", false, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "*[0-9]\\2-3", "abc12", true, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "*[0-9]\\2-3", "abc1", false, nil, shwild.AllowRangeQuantification)
+
+	// digits and hyphens that follow a quantifier must be in a range
+
+	check_Match(t, "[0-9]\\4[-][0-9]\\2", "2024-10", true, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "[0-9]\\2[5]", "125", true, nil, shwild.AllowRangeQuantification)
+
+	// other flags apply to the repeated item
+
+	check_Match(t, "[a-c]\\2", "aB", true, nil, shwild.AllowRangeQuantification, shwild.IgnoreCase)
+	check_Match(t, "x\\2", "xX", true, nil, shwild.AllowRangeQuantification, shwild.IgnoreCase)
+	check_Match(t, "x\\2", "xX", false, nil, shwild.AllowRangeQuantification)
+}
+
+func Test_Match_with_invalid_quantifiers(t *testing.T) {
+
+	check_Match_PatternError(t, "\\3", "", shwild.InvalidQuantifier, 0, shwild.AllowRangeQuantification)
+	check_Match_PatternError(t, "*\\3", "", shwild.InvalidQuantifier, 1, shwild.AllowRangeQuantification)
+	check_Match_PatternError(t, "a\\3\\2", "", shwild.InvalidQuantifier, 3, shwild.AllowRangeQuantification)
+	check_Match_PatternError(t, "?\\3\\2", "", shwild.InvalidQuantifier, 3, shwild.AllowRangeQuantification)
+	check_Match_PatternError(t, "a\\3-2", "", shwild.InvalidQuantifier, 1, shwild.AllowRangeQuantification)
+	check_Match_PatternError(t, "a\\-", "", shwild.InvalidQuantifier, 1, shwild.AllowRangeQuantification)
+	check_Match_PatternError(t, "a\\1-2-3", "", shwild.InvalidQuantifier, 1, shwild.AllowRangeQuantification)
+	check_Match_PatternError(t, "a\\1001", "", shwild.InvalidQuantifier, 1, shwild.AllowRangeQuantification)
+
+	check_Match(t, "a\\1000", strings.Repeat("a", 1000), true, nil, shwild.AllowRangeQuantification)
+}
+
+func Test_Match_with_pattern_too_large(t *testing.T) {
+
+	// each quantifier is within its limit, but the pattern as a whole
+	// would compile to a million instructions
+
+	pattern := strings.Repeat("[a-z]\\0-1000", 500)
+
+	check_Match_PatternError(t, pattern, "", shwild.PatternTooLarge, 0, shwild.AllowRangeQuantification)
+
+	if _, err := shwild.Compile(pattern, shwild.AllowRangeQuantification); !errors.Is(err, shwild.PatternTooLarge) {
+
+		t.Errorf("Compile() returned %v; %v expected", err, shwild.PatternTooLarge)
+	}
+
+	check_Match(t, strings.Repeat("[a-z]\\0-1000", 20), "abc", true, nil, shwild.AllowRangeQuantification)
+}
+
+func Test_Match_with_character_classes(t *testing.T) {
+
+	check_Match(t, "[[:alpha:]]", "a", true, nil)
+	check_Match(t, "[[:alpha:]]", "\u00e9", true, nil)
+	check_Match(t, "[[:alpha:]]", "\u0436", true, nil)
+	check_Match(t, "[[:alpha:]]", "1", false, nil)
+	check_Match(t, "[[:digit:]]", "7", true, nil)
+	check_Match(t, "[[:digit:]]", "\u0667", true, nil)
+	check_Match(t, "[[:digit:]]", "x", false, nil)
+	check_Match(t, "[[:alnum:]]", "x", true, nil)
+	check_Match(t, "[[:alnum:]]", "7", true, nil)
+	check_Match(t, "[[:alnum:]]", "_", false, nil)
+	check_Match(t, "[[:upper:]]", "\u00c9", true, nil)
+	check_Match(t, "[[:upper:]]", "\u00e9", false, nil)
+	check_Match(t, "[[:lower:]]", "\u00e9", true, nil)
+	check_Match(t, "[[:lower:]]", "\u00c9", false, nil)
+	check_Match(t, "[[:space:]]", " ", true, nil)
+	check_Match(t, "[[:space:]]", "\n", true, nil)
+	check_Match(t, "[[:space:]]", "\u00a0", true, nil)
+	check_Match(t, "[[:space:]]", "x", false, nil)
+	check_Match(t, "[[:blank:]]", "\t", true, nil)
+	check_Match(t, "[[:blank:]]", "\n", false, nil)
+	check_Match(t, "[[:punct:]]", "!", true, nil)
+	check_Match(t, "[[:punct:]]", "$", true, nil)
+	check_Match(t, "[[:punct:]]", "\u00bf", true, nil)
+	check_Match(t, "[[:punct:]]", "a", false, nil)
+	check_Match(t, "[[:xdigit:]]", "F", true, nil)
+	check_Match(t, "[[:xdigit:]]", "g", false, nil)
+	check_Match(t, "[[:xdigit:]]", "\u0667", false, nil)
+	check_Match(t, "[[:cntrl:]]", "\x07", true, nil)
+	check_Match(t, "[[:cntrl:]]", " ", false, nil)
+	check_Match(t, "[[:graph:]]", "a", true, nil)
+	check_Match(t, "[[:graph:]]", " ", false, nil)
+	check_Match(t, "[[:print:]]", " ", true, nil)
+	check_Match(t, "[[:print:]]", "\x07", false, nil)
+
+	// classes in not-ranges
+
+	check_Match(t, "[^[:space:]]", "x", true, nil)
+	check_Match(t, "[^[:space:]]", " ", false, nil)
+	check_Match(t, "[^[:digit:]]", "\u0667", false, nil)
+
+	// classes combined with characters, continua, and other classes
+
+	check_Match(t, "[_[:alpha:]][_[:alnum:]]*", "_id2", true, nil)
+	check_Match(t, "[_[:alpha:]][_[:alnum:]]*", "2id", false, nil)
+	check_Match(t, "[[:digit:]a-f]", "c", true, nil)
+	check_Match(t, "[[:digit:]a-f]", "g", false, nil)
+	check_Match(t, "[[:digit:][:punct:]]", ".", true, nil)
+	check_Match(t, "[-[:digit:]]", "-", true, nil)
+	check_Match(t, "[[:digit:]-]", "-", true, nil)
+	check_Match(t, "[[:digit:]-]", "a", false, nil)
+
+	// invalid UTF-8 is a member of no class
+
+	check_Match(t, "[[:graph:]]", "\xff", false, nil)
+	check_Match(t, "[^[:graph:]]", "\xff", true, nil)
+
+	// other flags
+
+	check_Match(t, "[[:upper:]]", "a", true, nil, shwild.IgnoreCase)
+	check_Match(t, "[^[:lower:]]", "A", false, nil, shwild.IgnoreCase)
+	check_Match(t, "[[:digit:]]\\2", "42", true, nil, shwild.AllowRangeQuantification)
+	check_Match(t, "[[:digit:]]]", "4]", true, nil)
+	check_Match(t, "[[:digit:]]]", "]", true, nil, shwild.AllowRangeLiteralBracket)
+	check_Match(t, "[[:digit:]]]", "4]", false, nil, shwild.AllowRangeLiteralBracket)
+}
+
+func Test_Match_with_invalid_character_classes(t *testing.T) {
+
+	check_Match_PatternError(t, "[[:alhpa:]]", "", shwild.InvalidClass, 1)
+	check_Match_PatternError(t, "[[:ALPHA:]]", "", shwild.InvalidClass, 1)
+	check_Match_PatternError(t, "[[::]]", "", shwild.InvalidClass, 1)
+	check_Match_PatternError(t, "x[[:alpha]]", "", shwild.InvalidClass, 2)
+	check_Match_PatternError(t, "[a-[:digit:]]", "", shwild.InvalidContinuum, 2)
+	check_Match_PatternError(t, "[[:digit:]-z]", "", shwild.InvalidContinuum, 10)
+	check_Match_PatternError(t, "[[:digit:]-[:alpha:]]", "", shwild.InvalidContinuum, 10)
+	check_Match_PatternError(t, "[[:digit:]-]", "", shwild.LeadtrailHyphenInRange, 10, shwild.SuppressRangeLeadtrailLiteralHyphen)
+
+	// without continua, a hyphen is always literal
+
+	check_Match(t, "[a-[:digit:]]", "-", true, nil, shwild.SuppressRangeContinuumSupport)
+}
+
+func Test_Match_with_AllowBraceAlternation(t *testing.T) {
+
+	// without the flag, braces and commas are literal
+
+	check_Match(t, "*.{jpg,png}", "photo.jpg", false, nil)
+	check_Match(t, "*.{jpg,png}", "photo.{jpg,png}", true, nil)
+
+	check_Match(t, "*.{jpg,jpeg,png}", "photo.jpg", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "*.{jpg,jpeg,png}", "photo.jpeg", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "*.{jpg,jpeg,png}", "photo.png", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "*.{jpg,jpeg,png}", "photo.gif", false, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "*.{jpg,jpeg,png}", "photo.jpgpng", false, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "{src,test}/*.go", "test/main.go", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "{src,test}/*.go", "doc/main.go", false, nil, shwild.AllowBraceAlternation)
+
+	// branches may be empty, and may contain wildcards and ranges
+
+	check_Match(t, "a{,b}", "a", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "a{,b}", "ab", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "a{,b}", "abb", false, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "{*.c,[A-Z]?}", "main.c", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "{*.c,[A-Z]?}", "Xy", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "{*.c,[A-Z]?}", "xy", false, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "{[,}],x}", ",", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "{[,}],x}", "}", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "{[,}],x}", "x", true, nil, shwild.AllowBraceAlternation)
+
+	// nesting
+
+	check_Match(t, "{a,b{c,d}e}", "a", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "{a,b{c,d}e}", "bde", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "{a,b{c,d}e}", "be", false, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "{a,{b,{c,d}}}", "d", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "{a,{b,{c,d}}}", "e", false, nil, shwild.AllowBraceAlternation)
+
+	// escaped commas and braces
+
+	check_Match(t, "{a\\,b,c}", "a,b", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "{a\\,b,c}", "a", false, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "{a\\,b,c}", "c", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "{\\{,\\}}", "{", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "{\\{,\\}}", "}", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "\\{a,b}", "{a,b}", true, nil, shwild.AllowBraceAlternation)
+
+	// braces that do not form an alternation are literal
+
+	check_Match(t, "{abc}", "{abc}", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "{}", "{}", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "{a,b", "{a,b", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "a,b}", "a,b}", true, nil, shwild.AllowBraceAlternation)
+	check_Match(t, "{a,{b}}", "{b}", true, nil, shwild.AllowBraceAlternation)
+
+	// other flags apply within branches
+
+	check_Match(t, "*.{JPG,PNG}", "photo.jpg", true, nil, shwild.AllowBraceAlternation, shwild.IgnoreCase)
+	check_Match(t, "{a\\,b,c}", "a\\", true, nil, shwild.AllowBraceAlternation, shwild.SuppressBackslashEscape)
+	check_Match(t, "{[0-9]\\2,x}", "42", true, nil, shwild.AllowBraceAlternation, shwild.AllowRangeQuantification)
+
+	// errors within branches are reported against the whole pattern
+
+	check_Match_PatternError(t, "x{[a-c-e],b}", "", shwild.InvalidContinuum, 6, shwild.AllowBraceAlternation)
+	check_Match_PatternError(t, "x{a,\\3}", "", shwild.InvalidQuantifier, 4, shwild.AllowBraceAlternation, shwild.AllowRangeQuantification)
+	check_Match_PatternError(t, "{a,[b}", "", shwild.UnterminatedRange, 3, shwild.AllowBraceAlternation)
+}
+
+func Test_Match_with_PathMode(t *testing.T) {
+
+	// without the flag, wildcards match the separator
+
+	check_Match(t, "src/*.go", "src/a/b/c.go", true, nil)
+	check_Match(t, "*", "a/b", true, nil)
+
+	// *, ? and ranges do not match the separator
+
+	check_Match(t, "src/*.go", "src/main.go", true, nil, shwild.PathMode)
+	check_Match(t, "src/*.go", "src/a/main.go", false, nil, shwild.PathMode)
+	check_Match(t, "*", "a", true, nil, shwild.PathMode)
+	check_Match(t, "*", "a/b", false, nil, shwild.PathMode)
+	check_Match(t, "*/*", "a/b", true, nil, shwild.PathMode)
+	check_Match(t, "a?b", "a/b", false, nil, shwild.PathMode)
+	check_Match(t, "a?b", "a.b", true, nil, shwild.PathMode)
+	check_Match(t, "a[/.]b", "a/b", false, nil, shwild.PathMode)
+	check_Match(t, "a[^x]b", "a/b", false, nil, shwild.PathMode)
+	check_Match(t, "a[^x]b", "a.b", true, nil, shwild.PathMode)
+	check_Match(t, "*a*b", "a/ab", false, nil, shwild.PathMode)
+	check_Match(t, "*a*/*b", "xaya/zb", true, nil, shwild.PathMode)
+	check_Match(t, "*.go", ".go", true, nil, shwild.PathMode)
+
+	// ** as a whole segment matches zero or more directories
+
+	check_Match(t, "**/*.go", "main.go", true, nil, shwild.PathMode)
+	check_Match(t, "**/*.go", "a/main.go", true, nil, shwild.PathMode)
+	check_Match(t, "**/*.go", "a/b/c/main.go", true, nil, shwild.PathMode)
+	check_Match(t, "**/*.go", "a/b/c/main.c", false, nil, shwild.PathMode)
+	check_Match(t, "src/**/*.go", "src/main.go", true, nil, shwild.PathMode)
+	check_Match(t, "src/**/*.go", "src/a/b/main.go", true, nil, shwild.PathMode)
+	check_Match(t, "src/**/*.go", "test/src/main.go", false, nil, shwild.PathMode)
+	check_Match(t, "src/**/*.go", "srcmain.go", false, nil, shwild.PathMode)
+	check_Match(t, "src/**", "src", true, nil, shwild.PathMode)
+	check_Match(t, "src/**", "src/a", true, nil, shwild.PathMode)
+	check_Match(t, "src/**", "src/a/b", true, nil, shwild.PathMode)
+	check_Match(t, "src/**", "srcx", false, nil, shwild.PathMode)
+	check_Match(t, "**", "", true, nil, shwild.PathMode)
+	check_Match(t, "**", "a/b/c", true, nil, shwild.PathMode)
+	check_Match(t, "a/**/**", "a/b/c", true, nil, shwild.PathMode)
+	check_Match(t, "**/b/**", "a/b/c", true, nil, shwild.PathMode)
+	check_Match(t, "**/b/**", "a/c", false, nil, shwild.PathMode)
+
+	// ** other than as a whole segment is equivalent to *
+
+	check_Match(t, "a**", "abc", true, nil, shwild.PathMode)
+	check_Match(t, "a**", "a/b", false, nil, shwild.PathMode)
+	check_Match(t, "**.go", "a/b.go", false, nil, shwild.PathMode)
+	check_Match(t, "a/**b", "a/x/b", false, nil, shwild.PathMode)
+	check_Match(t, "\\**/b", "*x/b", true, nil, shwild.PathMode)
+
+	// ** at the start or end of a branch of an alternation is a whole
+	// segment if the alternation starts or ends one
+
+	check_Match(t, "{**,a}/x", "x", true, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{**,a}/x", "b/c/x", true, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{**,a}/x", "a/x", true, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{**,a}/x", "bx", false, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{**/,x/}*.go", "a/b/main.go", true, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{**/,x/}*.go", "main.go", true, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "x/{**,y}", "x", true, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "x/{**,y}", "x/a/b", true, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "x/{**,y}", "x/y", true, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{src/**,x}", "src/a/b", true, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{src/**,x}", "src", true, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{{**,a},b}/x", "c/d/x", true, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{b,**}/**", "b", true, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{b,**}/**", "c/d", true, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{a/**,b}c", "a/x/c", false, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{a/**,b}c", "a/xc", true, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "a{**,b}/x", "a/b/x", false, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "a{**,b}/x", "acc/x", true, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+
+	// ... but ** that adjoins an alternation from outside is not, whichever
+	// branch precedes or follows it
+
+	check_Match(t, "{a/,b}**", "a/x", true, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{a/,b}**", "a/x/y", false, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "**{/a,b}", "x/y/a", false, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+
+	// other flags
+
+	check_Match(t, "{src,test}/**/*.go", "test/a/a_test.go", true, nil, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "SRC/**/*.GO", "src/a/main.go", true, nil, shwild.PathMode, shwild.IgnoreCase)
+	check_Match(t, "?\\3/*", "abc/d", true, nil, shwild.PathMode, shwild.AllowRangeQuantification)
+	check_Match(t, "?\\3/*", "a/c/d", false, nil, shwild.PathMode, shwild.AllowRangeQuantification)
+}
+
+func Test_Match_with_ExplicitLeadingPeriod(t *testing.T) {
+
+	// without the flag, wildcards match a leading period
+
+	check_Match(t, "*", ".bashrc", true, nil)
+	check_Match(t, "?bashrc", ".bashrc", true, nil)
+
+	check_Match(t, "*", ".bashrc", false, nil, shwild.ExplicitLeadingPeriod)
+	check_Match(t, "*rc", ".bashrc", false, nil, shwild.ExplicitLeadingPeriod)
+	check_Match(t, "?bashrc", ".bashrc", false, nil, shwild.ExplicitLeadingPeriod)
+	check_Match(t, "[.]bashrc", ".bashrc", false, nil, shwild.ExplicitLeadingPeriod)
+	check_Match(t, "[^a]bashrc", ".bashrc", false, nil, shwild.ExplicitLeadingPeriod)
+	check_Match(t, ".*", ".bashrc", true, nil, shwild.ExplicitLeadingPeriod)
+	check_Match(t, "\\.*", ".bashrc", true, nil, shwild.ExplicitLeadingPeriod)
+	check_Match(t, "*", "bashrc", true, nil, shwild.ExplicitLeadingPeriod)
+	check_Match(t, "*.txt", "a.txt", true, nil, shwild.ExplicitLeadingPeriod)
+	check_Match(t, "*", "", true, nil, shwild.ExplicitLeadingPeriod)
+	check_Match(t, "{*,.*}", ".bashrc", true, nil, shwild.ExplicitLeadingPeriod, shwild.AllowBraceAlternation)
+	check_Match(t, "{?,.}", ".", true, nil, shwild.ExplicitLeadingPeriod, shwild.AllowBraceAlternation)
+
+	// a wildcard that precedes a leading period prevents its match, even
+	// if it matches nothing
+
+	check_Match(t, "*.go", ".go", false, nil, shwild.ExplicitLeadingPeriod)
+	check_Match(t, "*.go", "a.go", true, nil, shwild.ExplicitLeadingPeriod)
+	check_Match(t, "*.go", "..go", false, nil, shwild.ExplicitLeadingPeriod)
+
+	// without path mode, only the first character is protected
+
+	check_Match(t, "a/*", "a/.git", true, nil, shwild.ExplicitLeadingPeriod)
+
+	// in path mode, a period following a separator is protected
+
+	check_Match(t, "a/*", "a/.git", false, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode)
+	check_Match(t, "a/?git", "a/.git", false, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode)
+	check_Match(t, "a/.*", "a/.git", true, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode)
+	check_Match(t, "a/*", "a/b.git", true, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode)
+	check_Match(t, "*/b", ".a/b", false, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode)
+	check_Match(t, "**/*.go", ".git/main.go", false, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode)
+	check_Match(t, "**/*.go", "a/.git/main.go", false, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode)
+	check_Match(t, "**/*.go", "a/b/main.go", true, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode)
+	check_Match(t, "**/.git/*", "a/.git/config", true, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode)
+	check_Match(t, "a/**", "a/.git", false, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode)
+	check_Match(t, "**/.git", ".git", true, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode)
+	check_Match(t, "a/*.go", "a/.go", false, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode)
+
+	// a period that follows a separator in one branch of an alternation,
+	// and a wildcard in another, is explicit only in the former
+
+	check_Match(t, "{*,x/}.b", ".b", false, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{*,x/}.b", "x/.b", true, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{*,x/}.b", "a.b", true, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{x/,*}.b", ".b", false, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{x/,*}.b", "x/.b", true, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{*,x/}{,y}.b", ".b", false, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{*,x/}{,y}.b", "x/.b", true, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{*,x/}{,y}.b", "x/y.b", true, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{*,?}/.x", "a/.x", true, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{*,?}/.x", "/.x", true, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{*,?}/.x", "./.x", false, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{*,?/}.x", ".x", false, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{*,?/}.x", "a/.x", true, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{?,a/}.x", "b.x", true, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode, shwild.AllowBraceAlternation)
+	check_Match(t, "{?,a/}.x", "..x", false, nil, shwild.ExplicitLeadingPeriod, shwild.PathMode, shwild.AllowBraceAlternation)
+}
+
+func Test_Match_with_combined_flags(t *testing.T) {
+
+	check_Match(t, "[A-C]\\*", "b\\xyz", true, nil, shwild.IgnoreCase, shwild.SuppressBackslashEscape)
+	check_Match(t, "[A-C]\\*", "b\\xyz", true, nil, uint64(shwild.IgnoreCase|shwild.SuppressBackslashEscape))
+	check_Match(t, "[A-C]\\*", "b\\xyz", true, nil, uint32(shwild.IgnoreCase|shwild.SuppressBackslashEscape))
+}
+
 /* ///////////////////////////// end of file //////////////////////////// */
@@ -1,18 +1,19 @@
-// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2025
+// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2026
 // Matthew Wilson and Synesis Information Systems. All rights reserved. Use
 // of this source code is governed by a BSD-style license that can be found
 // in the LICENSE file.
 
 /*
  * Created: 17th June 2005
- * Updated: 24th February 2025
+ * Updated: 18th October 2026
  */
 
 package shwild
 
 import (
 	"fmt"
-	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 /* /////////////////////////////////////////////////////////////////////////
@@ -26,16 +27,14 @@ import (
 // matcher interface
 
 type matcher interface {
-	setNext(next matcher)
-
-	match(s string) bool
+	// Emits the instructions that implement the matcher into the program
+	compile(p *program)
 }
 
 // literal_matcher : matcher structure
 
 type literal_matcher struct {
 	node
-	next matcher
 }
 
 func make_literal_matcher(flags uint64, value string) matcher {
@@ -43,40 +42,25 @@ func make_literal_matcher(flags uint64, value string) matcher {
 	var m literal_matcher
 
 	m.node = make_node(_NODE_LITERAL, flags, value)
-	m.next = nil
 
 	return &m
 }
-func (m *literal_matcher) setNext(next matcher) {
-
-	m.next = next
-}
-func (m literal_matcher) match(s string) bool {
-
-	l := len(m.node.data)
-
-	if len(s) < l {
-
-		return false
-	}
+func (m literal_matcher) compile(p *program) {
 
-	// TODO: do case-(in)sensitive
+	for s := m.node.data; 0 != len(s); {
 
-	if m.node.data == s[0:l] {
+		c, w := decode_char(s)
 
-		rest := s[l:]
+		p.emit(inst{op: _OP_CHAR, flags: m.node.flags, r: c})
 
-		return m.next.match(rest)
+		s = s[w:]
 	}
-
-	return false
 }
 
 // wild1_matcher : matcher structure
 
 type wild1_matcher struct {
 	node
-	next matcher
 }
 
 func make_wild1_matcher(flags uint64, value string) matcher {
@@ -87,25 +71,15 @@ func make_wild1_matcher(flags uint64, value string) matcher {
 
 	return &m
 }
-func (m *wild1_matcher) setNext(next matcher) {
-
-	m.next = next
-}
-func (m wild1_matcher) match(s string) bool {
-
-	if len(s) < 1 {
-
-		return false
-	}
+func (m wild1_matcher) compile(p *program) {
 
-	return m.next.match(s[1:])
+	p.emit(inst{op: _OP_ANY, flags: m.node.flags})
 }
 
 // wildN_matcher : matcher structure
 
 type wildN_matcher struct {
 	node
-	next matcher
 }
 
 func make_wildN_matcher(flags uint64, value string) matcher {
@@ -116,89 +90,232 @@ func make_wildN_matcher(flags uint64, value string) matcher {
 
 	return &m
 }
-func (m *wildN_matcher) setNext(next matcher) {
+func (m wildN_matcher) compile(p *program) {
+
+	// L1: split L2, L3
+	// L2: any; goto L1
+	// L3:
+
+	split := p.emit(inst{op: _OP_SPLIT, flags: m.node.flags})
+	any := p.emit(inst{op: _OP_ANY, flags: m.node.flags})
 
-	m.next = next
+	p.insts[any].out = split
+	p.insts[split].arg = len(p.insts)
 }
-func (m wildN_matcher) match(s string) bool {
 
-	for i := 0; i != 1+len(s); i++ {
+// wilddirs_matcher : matcher structure
 
-		if m.next.match(s[i:]) {
+type wilddirs_matcher struct {
+	node
+}
 
-			return true
-		}
-	}
+func make_wilddirs_matcher(flags uint64, value string) matcher {
 
-	return false
+	var m wilddirs_matcher
+
+	m.node = make_node(_NODE_WILD_DIRS, flags, value)
+
+	return &m
+}
+func (m wilddirs_matcher) compile(p *program) {
+
+	// the characters of the directories may include the separator
+
+	flags := m.node.flags &^ PathMode
+
+	switch m.node.data {
+
+	case "**/":
+
+		// L1: split L2, L5
+		// L2: split L3, L4
+		// L3: any; goto L2
+		// L4: char '/'
+		// L5:
+
+		split := p.emit(inst{op: _OP_SPLIT, flags: flags})
+		loop := p.emit(inst{op: _OP_SPLIT, flags: flags})
+		any := p.emit(inst{op: _OP_ANY, flags: flags})
+		p.emit(inst{op: _OP_CHAR, flags: flags, r: p.separator})
+
+		p.insts[any].out = loop
+		p.insts[loop].arg = any + 1
+		p.insts[split].arg = len(p.insts)
+	case "/**":
+
+		// L1: split L2, L5
+		// L2: char '/'
+		// L3: split L4, L5
+		// L4: any; goto L3
+
+		split := p.emit(inst{op: _OP_SPLIT, flags: flags})
+		p.emit(inst{op: _OP_CHAR, flags: flags, r: p.separator})
+		loop := p.emit(inst{op: _OP_SPLIT, flags: flags})
+		any := p.emit(inst{op: _OP_ANY, flags: flags})
+
+		p.insts[any].out = loop
+		p.insts[loop].arg = len(p.insts)
+		p.insts[split].arg = len(p.insts)
+	default:
+
+		make_wildN_matcher(flags, "").compile(p)
+	}
 }
 
 // range_matcher : matcher structure
 
 type range_matcher struct {
 	node
-	next matcher
 }
 
-func make_range_matcher(flags uint64, value string) matcher {
+func make_range_matcher(flags uint64, value string, runes rune_set, classes []char_class) matcher {
 
 	var m range_matcher
 
-	m.node = make_range_node(_NODE_RANGE, flags, value)
+	m.node = make_node(_NODE_RANGE, flags, value)
+	m.node.runes = runes
+	m.node.classes = classes
 
 	return &m
 }
-func (m *range_matcher) setNext(next matcher) {
+func (m range_matcher) compile(p *program) {
 
-	m.next = next
+	p.emit(inst{op: _OP_RANGE, flags: m.node.flags, n: m.node})
 }
-func (m range_matcher) match(s string) bool {
 
-	if len(s) < 1 {
+// notrange_matcher : matcher structure
 
-		return false
+type notrange_matcher struct {
+	node
+}
+
+func make_notrange_matcher(flags uint64, value string, runes rune_set, classes []char_class) matcher {
+
+	var m notrange_matcher
+
+	m.node = make_node(_NODE_NOT_RANGE, flags, value)
+	m.node.runes = runes
+	m.node.classes = classes
+
+	return &m
+}
+func (m notrange_matcher) compile(p *program) {
+
+	p.emit(inst{op: _OP_NOT_RANGE, flags: m.node.flags, n: m.node})
+}
+
+// repeat_matcher : matcher structure
+
+type repeat_matcher struct {
+	node
+	inner matcher
+}
+
+func make_repeat_matcher(n node, inner matcher) matcher {
+
+	var m repeat_matcher
+
+	m.node = n
+	m.inner = inner
+
+	return &m
+}
+func (m repeat_matcher) compile(p *program) {
+
+	// the mandatory repetitions
+
+	for i := 0; m.node.min != i; i++ {
+
+		m.inner.compile(p)
 	}
 
-	if !strings.Contains(m.node.data, s[0:1]) {
+	if _REPEAT_UNBOUNDED == m.node.max {
 
-		return false
+		// L1: split L2, L3
+		// L2: <inner>; goto L1
+		// L3:
+
+		split := p.emit(inst{op: _OP_SPLIT, flags: m.node.flags})
+
+		m.inner.compile(p)
+
+		p.insts[len(p.insts)-1].out = split
+		p.insts[split].arg = len(p.insts)
+
+		return
 	}
 
-	return m.next.match(s[1:])
+	// the optional repetitions, each of which may be skipped to the end
+
+	var splits []int
+
+	for i := m.node.min; m.node.max != i; i++ {
+
+		splits = append(splits, p.emit(inst{op: _OP_SPLIT, flags: m.node.flags}))
+
+		m.inner.compile(p)
+	}
+
+	for _, split := range splits {
+
+		p.insts[split].arg = len(p.insts)
+	}
 }
 
-// notrange_matcher : matcher structure
+// alternation_matcher : matcher structure
 
-type notrange_matcher struct {
+type alternation_matcher struct {
 	node
-	next matcher
+	branches [][]matcher
 }
 
-func make_notrange_matcher(flags uint64, value string) matcher {
+func make_alternation_matcher(n node, branches [][]matcher) matcher {
 
-	var m notrange_matcher
+	var m alternation_matcher
 
-	m.node = make_node(_NODE_NOT_RANGE, flags, value)
+	m.node = n
+	m.branches = branches
 
 	return &m
 }
-func (m *notrange_matcher) setNext(next matcher) {
+func (m alternation_matcher) compile(p *program) {
 
-	m.next = next
-}
-func (m notrange_matcher) match(s string) bool {
+	// L1: split L2, L3
+	// L2: <branch-1>; goto L5
+	// L3: split L4, ...
+	// L4: <branch-2>; goto L5
+	// ...
+	//     <branch-N>
+	// L5:
 
-	if len(s) < 1 {
+	var jumps []int
 
-		return false
-	}
+	for i, branch := range m.branches {
 
-	if strings.Contains(m.node.data, s[0:1]) {
+		var split int
 
-		return false
+		if i+1 != len(m.branches) {
+
+			split = p.emit(inst{op: _OP_SPLIT, flags: m.node.flags})
+		}
+
+		for _, bm := range branch {
+
+			p.compile_matcher(bm)
+		}
+
+		if i+1 != len(m.branches) {
+
+			jumps = append(jumps, p.emit(inst{op: _OP_JUMP, flags: m.node.flags}))
+
+			p.insts[split].arg = len(p.insts)
+		}
 	}
 
-	return m.next.match(s[1:])
+	for _, jump := range jumps {
+
+		p.insts[jump].out = len(p.insts)
+	}
 }
 
 // end_matcher : matcher structure
@@ -215,13 +332,9 @@ func make_end_matcher(flags uint64) matcher {
 
 	return &m
 }
-func (m *end_matcher) setNext(_ matcher) {
+func (m end_matcher) compile(p *program) {
 
-	panic("end_matcher should never be asked to setNext()")
-}
-func (m end_matcher) match(s string) bool {
-
-	return 0 == len(s)
+	p.emit(inst{op: _OP_MATCH, flags: m.node.flags})
 }
 
 /* /////////////////////////////////////////////////////////////////////////
@@ -232,58 +345,248 @@ func (m end_matcher) match(s string) bool {
  * internal functions
  */
 
-func parse_matchers(pattern string, flags uint64) ([]matcher, error) {
+// Determines whether the range node contains the character r, taking
+// into account case-insensitivity.
+//
+// An invalid UTF-8 sequence (see decode_char()) is a member of no range,
+// and is therefore matched only by '?', '*', and not-ranges.
+func range_contains(n node, r rune) bool {
 
-	if 0 == len(pattern) {
+	if r < 0 {
 
-		return nil, nil
+		return false
 	}
 
-	// create the sequence of matchers
+	if range_contains_exactly(n, r) {
 
-	var matchers []matcher
+		return true
+	}
+
+	if 0 != (IgnoreCase & n.flags) {
+
+		// visit every other member of the character's simple case-folding
+		// orbit, e.g. 'k' => 'K' => KELVIN SIGN => 'k'
 
-	nodes, err := parse_nodes(pattern, flags)
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+
+			if range_contains_exactly(n, f) {
+
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Determines whether the range node contains the character r, either as
+// one of its characters or as a member of one of its character classes.
+func range_contains_exactly(n node, r rune) bool {
+
+	if n.runes.contains(r) {
+
+		return true
+	}
+
+	for _, cc := range n.classes {
+
+		if cc.contains(r) {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// Determines whether two characters are equivalent under Unicode simple
+// case folding.
+func fold_equal(r1, r2 rune) bool {
+
+	if r1 == r2 {
+
+		return true
+	}
+
+	for f := unicode.SimpleFold(r1); f != r1; f = unicode.SimpleFold(f) {
+
+		if f == r2 {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// Determines whether the matcher is a wildcard - ?, *, **, a range or a
+// not-range, quantified or not - and so is captured.
+func is_wildcard_matcher(m matcher) bool {
+
+	switch m := m.(type) {
+
+	case *wild1_matcher, *wildN_matcher, *wilddirs_matcher, *range_matcher, *notrange_matcher:
+
+		return true
+	case *repeat_matcher:
+
+		return is_wildcard_matcher(m.inner)
+	}
+
+	return false
+}
+
+func parse_matchers(pattern string, flags uint64, separator rune) ([]matcher, error) {
+
+	if 0 == len(pattern) {
+
+		return nil, nil
+	}
+
+	nodes, err := parse_nodes(pattern, flags, separator)
 
 	if nil != err {
 
 		return nil, err
 	}
 
+	matchers := make_matchers(nodes, flags)
+
+	if limit := program_limit(pattern); matchers_size(matchers, limit) > limit {
+
+		return nil, make_pattern_error(pattern, 0, PatternTooLarge)
+	}
+
+	return matchers, nil
+}
+
+// Obtains the maximum number of instructions to which patterns may compile
+// together, which is sufficient for any patterns without quantifiers, since
+// no other element compiles to more than two instructions per character.
+func program_limit(patterns ...string) int {
+
+	limit := _PROGRAM_LIMIT
+
+	for _, pattern := range patterns {
+
+		limit += 2*len(pattern) + 1
+	}
+
+	return limit
+}
+
+// Obtains the number of instructions to which the matchers compile - or,
+// for a wilddirs matcher, at most compile - or a number greater than limit
+// if that is exceeded.
+func matchers_size(matchers []matcher, limit int) int {
+
+	size := 0
+
+	for _, m := range matchers {
+
+		if size += matcher_size(m, limit); size > limit {
+
+			break
+		}
+	}
+
+	return size
+}
+
+func matcher_size(m matcher, limit int) int {
+
+	switch m := m.(type) {
+
+	case *literal_matcher:
+
+		return utf8.RuneCountInString(m.node.data)
+	case *wildN_matcher:
+
+		return 2
+	case *wilddirs_matcher:
+
+		return 4
+	case *alternation_matcher:
+
+		// a split and a jump for each branch but the last
+
+		size := -2
+
+		for _, branch := range m.branches {
+
+			if size += 2 + matchers_size(branch, limit); size > limit {
+
+				break
+			}
+		}
+
+		return size
+	case *repeat_matcher:
+
+		inner := min(matcher_size(m.inner, limit), limit+1)
+
+		if _REPEAT_UNBOUNDED == m.node.max {
+
+			return (1+m.node.min)*inner + 1
+		}
+
+		return m.node.min*inner + (m.node.max-m.node.min)*(1+inner)
+	default:
+
+		return 1
+	}
+}
+
+// Creates the sequence of matchers corresponding to the given nodes.
+func make_matchers(nodes []node, flags uint64) []matcher {
+
+	var matchers []matcher
+
 	for _, n := range nodes {
 
+		var m matcher
+
 		switch n.node_type {
 
 		case _NODE_NOTHING:
-			break
+			continue
 		case _NODE_WILD_1:
-			matchers = append(matchers, make_wild1_matcher(flags, n.data))
+			m = make_wild1_matcher(flags, n.data)
 		case _NODE_WILD_N:
-			matchers = append(matchers, make_wildN_matcher(flags, n.data))
+			m = make_wildN_matcher(flags, n.data)
+		case _NODE_WILD_DIRS:
+			m = make_wilddirs_matcher(flags, n.data)
 		case _NODE_RANGE:
-			matchers = append(matchers, make_range_matcher(flags, n.data))
+			m = make_range_matcher(flags, n.data, n.runes, n.classes)
 		case _NODE_NOT_RANGE:
-			matchers = append(matchers, make_notrange_matcher(flags, n.data))
+			m = make_notrange_matcher(flags, n.data, n.runes, n.classes)
 		case _NODE_LITERAL:
-			matchers = append(matchers, make_literal_matcher(flags, n.data))
+			m = make_literal_matcher(flags, n.data)
+		case _NODE_ALTERNATION:
+
+			branches := make([][]matcher, len(n.branches))
+
+			for i, branch := range n.branches {
+
+				branches[i] = make_matchers(branch, flags)
+			}
+
+			m = make_alternation_matcher(n, branches)
 		case _NODE_END:
-			matchers = append(matchers, make_end_matcher(flags))
+			m = make_end_matcher(flags)
 		default:
 			panic(fmt.Sprintf("VIOLATION: unexpected node type %v", n.node_type))
 		}
-	}
-
-	// tie the sequence of matchers together
 
-	for i, m := range matchers {
+		if n.is_quantified() {
 
-		if 0 != i {
-
-			matchers[i-1].setNext(m)
+			m = make_repeat_matcher(n, m)
 		}
+
+		matchers = append(matchers, m)
 	}
 
-	return matchers, nil
+	return matchers
 }
 
 /* ///////////////////////////// end of file //////////////////////////// */
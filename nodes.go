@@ -1,20 +1,20 @@
-// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2025
+// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2026
 // Matthew Wilson and Synesis Information Systems. All rights reserved. Use
 // of this source code is governed by a BSD-style license that can be found
 // in the LICENSE file.
 
 /*
  * Created: 17th June 2005
- * Updated: 24th February 2025
+ * Updated: 18th October 2026
  */
 
 package shwild
 
 import (
-	"bytes"
 	"fmt"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 /* /////////////////////////////////////////////////////////////////////////
@@ -82,9 +82,11 @@ const (
 	_NODE_NOTHING _NodeType = iota
 	_NODE_WILD_1
 	_NODE_WILD_N
+	_NODE_WILD_DIRS
 	_NODE_RANGE
 	_NODE_NOT_RANGE
 	_NODE_LITERAL
+	_NODE_ALTERNATION
 	_NODE_END
 )
 
@@ -98,12 +100,16 @@ func (nt _NodeType) String() string {
 		return "_NODE_WILD_1"
 	case _NODE_WILD_N:
 		return "_NODE_WILD_N"
+	case _NODE_WILD_DIRS:
+		return "_NODE_WILD_DIRS"
 	case _NODE_RANGE:
 		return "_NODE_RANGE"
 	case _NODE_NOT_RANGE:
 		return "_NODE_NOT_RANGE"
 	case _NODE_LITERAL:
 		return "_NODE_LITERAL"
+	case _NODE_ALTERNATION:
+		return "_NODE_ALTERNATION"
 	case _NODE_END:
 		return "_NODE_END"
 	}
@@ -117,97 +123,164 @@ type node struct {
 	node_type _NodeType
 	flags     uint64
 	data      string
+	runes     rune_set     // the characters of a range
+	classes   []char_class // the character classes of a range
+	branches  [][]node     // the branches of an alternation
+	min       int          // minimum number of repetitions
+	max       int          // maximum number of repetitions, or _REPEAT_UNBOUNDED
 }
 
+const (
+	_REPEAT_UNBOUNDED = -1
+	_REPEAT_LIMIT     = 1000
+	_PROGRAM_LIMIT    = 100000 // the instructions permitted beyond two per character
+)
+
 func (n node) String() string {
 
-	return fmt.Sprintf("<%T{ node_type=%v, flags=0x%x, data=%q}>", n, n.node_type, n.flags, n.data)
+	return fmt.Sprintf("<%T{ node_type=%v, flags=0x%x, data=%q, runes=%v, classes=%v, branches=%v, min=%d, max=%d}>", n, n.node_type, n.flags, n.data, n.runes, n.classes, n.branches, n.min, n.max)
 }
 
 func make_node(node_type _NodeType, flags uint64, data string) (n node) {
 
-	return node{node_type: node_type, flags: flags, data: data}
+	return node{node_type: node_type, flags: flags, data: data, min: 1, max: 1}
+}
+
+// Determines whether the node is subject to a quantifier.
+func (n node) is_quantified() bool {
+
+	return 1 != n.min || 1 != n.max
+}
+
+// sequence_bounds structure
+//
+// The path segment boundaries at either end of a sequence - the whole
+// pattern, or a branch of an alternation - so that a ** at either end of a
+// branch is a directory wildcard where it would be were the alternation
+// expanded, as in "{**,src}/*.go". A separator that adjoins the alternation
+// is moved into each of its branches - leading or trailing - so that the
+// ** may consume it.
+
+type sequence_bounds struct {
+	at_start bool // the sequence begins a path segment
+	at_end   bool // the sequence ends a path segment
+	lead     bool // the sequence is preceded by a separator moved into it
+	trail    bool // the sequence is followed by a separator moved into it
+}
+
+func make_range_node(node_type _NodeType, flags uint64, data string, classes []char_class) (n node) {
+
+	n = make_node(node_type, flags, data)
+	n.runes = make_rune_set(flags, data)
+	n.classes = classes
+
+	return
 }
 
-func make_range_node(node_type _NodeType, flags uint64, data string) (n node) {
+// Obtains the set of the characters of the range data, in which each
+// continuum is represented as an interval, according to the continuum
+// flags.
+func make_rune_set(flags uint64, data string) (rs rune_set) {
 
-	if strings.ContainsRune(data[1:], '-') {
+	runes := []rune(data)
 
-		end_index := len(data) - 1
-		var buff bytes.Buffer
-		var from_rune rune
-		from_index := -1
+	for i := 0; i != len(runes); i++ {
 
-		for ix, ch := range data {
+		ch := runes[i]
 
-			if '-' == ch && (0 != ix && end_index != ix) {
+		// a continuum requires a character on either side of the hyphen
 
-				from_index = ix - 1
+		if 0 == (SuppressRangeContinuumSupport&flags) && i+2 < len(runes) && '-' == runes[i+1] {
+
+			if add_continuum(&rs, flags, ch, runes[i+2]) {
+
+				i += 2
 
 				continue
 			}
+		}
 
-			if from_index+2 == ix {
+		rs.add(ch, ch)
+	}
 
-				to_rune := ch
+	rs.normalise()
 
-				if unicode.IsLetter(from_rune) && unicode.IsLetter(to_rune) && unicode.IsLower(from_rune) != unicode.IsLower(to_rune) {
+	return
+}
 
-					// Have to treat this differently
+// Determines whether the continuum [from_rune, to_rune] is cross-case,
+// according to the continuum flags.
+func is_crosscase_continuum(flags uint64, from_rune, to_rune rune) bool {
 
-					var from_lower = int(unicode.ToLower(from_rune))
-					var to_lower = int(unicode.ToLower(to_rune))
+	if 0 != (SuppressRangeContinuumCrosscaseSupport & flags) {
 
-					var from_upper = int(unicode.ToUpper(from_rune))
-					var to_upper = int(unicode.ToUpper(to_rune))
+		return false
+	}
 
-					if to_lower < from_lower {
+	return unicode.IsLetter(from_rune) && unicode.IsLetter(to_rune) && unicode.IsLower(from_rune) != unicode.IsLower(to_rune)
+}
 
-						from_lower, to_lower = to_lower, from_lower
-					}
+// Determines whether the given pair constitute a continuum, according to
+// the continuum flags.
+func is_continuum(flags uint64, from_rune, to_rune rune) bool {
 
-					if to_upper < from_upper {
+	if 0 == (SuppressRangeContinuumHighlowSupport & flags) {
 
-						from_upper, to_upper = to_upper, from_upper
-					}
+		return true
+	}
 
-					write_range(&buff, from_lower, to_lower+1)
-					write_range(&buff, from_upper, to_upper+1)
+	if is_crosscase_continuum(flags, from_rune, to_rune) {
 
-					continue
-				}
+		return unicode.ToLower(from_rune) <= unicode.ToLower(to_rune)
+	}
 
-				var from int = int(from_rune)
-				var to int = int(to_rune)
+	return from_rune <= to_rune
+}
 
-				if to < from {
+// Adds the characters of the continuum [from_rune, to_rune], according to
+// the continuum flags, returning false if the given pair does not
+// constitute a continuum (in which case nothing is added).
+func add_continuum(rs *rune_set, flags uint64, from_rune, to_rune rune) bool {
 
-					from, to = to, from
-				}
+	if !is_continuum(flags, from_rune, to_rune) {
 
-				if from < to {
+		return false
+	}
 
-					write_range(&buff, from, to)
-				}
-			}
+	if is_crosscase_continuum(flags, from_rune, to_rune) {
+
+		// Have to treat this differently
+
+		var from_lower = unicode.ToLower(from_rune)
+		var to_lower = unicode.ToLower(to_rune)
 
-			buff.WriteRune(ch)
-			from_rune = ch
+		var from_upper = unicode.ToUpper(from_rune)
+		var to_upper = unicode.ToUpper(to_rune)
+
+		if to_lower < from_lower {
+
+			from_lower, to_lower = to_lower, from_lower
 		}
 
-		return make_node(node_type, flags, buff.String())
-	} else {
+		if to_upper < from_upper {
 
-		return make_node(node_type, flags, data)
-	}
-}
+			from_upper, to_upper = to_upper, from_upper
+		}
 
-func write_range(buff *bytes.Buffer, from, to int) {
+		rs.add(from_lower, to_lower)
+		rs.add(from_upper, to_upper)
 
-	for i := from; i != to; i++ {
+		return true
+	}
+
+	if to_rune < from_rune {
 
-		buff.WriteRune(rune(i))
+		from_rune, to_rune = to_rune, from_rune
 	}
+
+	rs.add(from_rune, to_rune)
+
+	return true
 }
 
 /* /////////////////////////////////////////////////////////////////////////
@@ -218,37 +291,233 @@ func write_range(buff *bytes.Buffer, from, to int) {
  * internal functions
  */
 
-func parse_nodes(pattern string, flags uint64) (nodes []node, err error) {
+func parse_nodes(pattern string, flags uint64, separator rune) (nodes []node, err error) {
+
+	if nodes, err = parse_sequence(pattern, 0, flags, separator, sequence_bounds{at_start: true, at_end: true}); nil != err {
+
+		return nil, err
+	}
+
+	node := make_node(_NODE_END, flags, "")
+	nodes = append(nodes, node)
+
+	return
+}
+
+// Parses pattern[beg:] into a sequence of nodes, which is either the whole
+// pattern or - when pattern is truncated at its closing brace - a branch of
+// an alternation, whose bounds are as given.
+func parse_sequence(pattern string, beg int, flags uint64, separator rune, bounds sequence_bounds) (nodes []node, err error) {
 
 	state := _TOK_LITERAL
 	prev_state := _TOK_LITERAL
 
-	var data []rune
+	var data []byte
+
+	if bounds.lead {
+
+		data = append(data, byte(separator))
+	}
+
+	var classes []char_class
+
+	// byte offsets of the characters of the current range
+
+	var offsets []int
+
+	// byte offsets of the most recent escape and range, for error reporting
+
+	escape_ix := -1
+	range_ix := -1
 
-	for _, ch := range pattern {
+	// the end of a quantifier, character class, or alternation, whose
+	// characters have already been consumed
+
+	skip_to := 0
+
+	for ix, ch := range pattern[beg:] {
+
+		ix += beg
+
+		if ix < skip_to {
+
+			continue
+		}
 
 		switch state {
 
 		case _TOK_ESCAPED_:
 
 			state = prev_state
-			data = append(data, ch)
+			data = append_char(data, pattern, ix, ch)
 		case _TOK_LITERAL, _TOK_START:
 
-			switch ch {
+			switch {
 
-			case '\\':
+			case '\\' == ch && 0 == (SuppressBackslashEscape&flags):
+
+				if 0 != (AllowRangeQuantification & flags) {
+
+					if q := scan_quantifier(pattern[ix+1:]); 0 != len(q) {
+
+						if nodes, err = apply_quantifier(pattern, ix, q, nodes, data, flags); nil != err {
+
+							return nil, err
+						}
+
+						data = make([]byte, 0)
+						state = _TOK_START
+						skip_to = ix + 1 + len(q)
+
+						continue
+					}
+				}
 
 				prev_state = state
 				state = _TOK_ESCAPED_
+				escape_ix = ix
+
+			case '{' == ch && 0 != (AllowBraceAlternation&flags):
+
+				alt := scan_alternation(pattern, ix, flags)
+
+				if nil == alt {
+
+					// a brace that does not start an alternation is literal
+
+					state = _TOK_LITERAL
+					data = append_char(data, pattern, ix, ch)
+
+					continue
+				}
+
+				end := alt[len(alt)-1]
+
+				var branch_bounds sequence_bounds
+
+				if has_edge_dirs_wildcard(pattern, alt, flags) {
+
+					// a separator that precedes the alternation is moved
+					// into its branches; one that follows it is moved only
+					// if there is none that precedes it
+
+					if l := len(data); 0 != l && separator == rune(data[l-1]) {
+
+						branch_bounds.lead = true
+						data = data[:l-1]
+					}
+
+					switch {
+
+					case branch_bounds.lead:
+
+						branch_bounds.at_start = true
+					case ix == beg:
+
+						branch_bounds.at_start = bounds.at_start
+					default:
+
+						branch_bounds.at_start = separator == rune(pattern[ix-1])
+					}
+
+					switch rest := pattern[end+1:]; {
 
-			case '?', '*', '[':
+					case 0 == len(rest):
+
+						branch_bounds.at_end = bounds.at_end
+						branch_bounds.trail, bounds.trail = bounds.trail, false
+					case separator == rune(rest[0]):
+
+						branch_bounds.at_end = true
+
+						// unless it precedes a ** that ends the sequence,
+						// which consumes it instead
+
+						if !branch_bounds.lead && !(bounds.at_end && "**" == rest[1:]) {
+
+							branch_bounds.trail = true
+							end++
+						}
+					}
+				}
 
 				if 0 != len(data) {
 
 					node := make_node(_NODE_LITERAL, flags, string(data))
 					nodes = append(nodes, node)
-					data = make([]rune, 0)
+					data = make([]byte, 0)
+				}
+
+				n := make_node(_NODE_ALTERNATION, flags, "")
+
+				for i := 0; i+1 != len(alt); i++ {
+
+					var branch []node
+
+					if branch, err = parse_sequence(pattern[:alt[i+1]], alt[i]+1, flags, separator, branch_bounds); nil != err {
+
+						// report the whole pattern, rather than its truncation
+
+						if pe, ok := err.(*PatternError); ok {
+
+							pe.Pattern = pattern
+						}
+
+						return nil, err
+					}
+
+					n.branches = append(n.branches, branch)
+				}
+
+				nodes = append(nodes, n)
+				state = _TOK_START
+				skip_to = end + 1
+			case '*' == ch && is_dirs_wildcard(pattern, beg, ix, flags, separator, bounds):
+
+				// ** as a whole path segment, which takes one of the forms
+				// "**/" (which consumes its separator), "/**" (which
+				// consumes the preceding separator), or "**"
+
+				form := "**"
+
+				if ix+2 != len(pattern) {
+
+					form = "**/"
+					skip_to = ix + 3
+				} else {
+
+					skip_to = ix + 2
+
+					if bounds.trail {
+
+						// the separator moved in from after the alternation
+
+						form = "**/"
+						bounds.trail = false
+					} else if l := len(data); 0 != l && separator == rune(data[l-1]) {
+
+						form = "/**"
+						data = data[:l-1]
+					}
+				}
+
+				if 0 != len(data) {
+
+					node := make_node(_NODE_LITERAL, flags, string(data))
+					nodes = append(nodes, node)
+					data = make([]byte, 0)
+				}
+
+				node := make_node(_NODE_WILD_DIRS, flags, form)
+				nodes = append(nodes, node)
+				state = _TOK_START
+			case '?' == ch, '*' == ch, '[' == ch && 0 == (SuppressRangeSupport&flags):
+
+				if 0 != len(data) {
+
+					node := make_node(_NODE_LITERAL, flags, string(data))
+					nodes = append(nodes, node)
+					data = make([]byte, 0)
 				}
 
 				switch ch {
@@ -264,45 +533,80 @@ func parse_nodes(pattern string, flags uint64) (nodes []node, err error) {
 				case '[':
 
 					state = _TOK_RANGE_BEG
+					range_ix = ix
 				}
 			default:
 
 				state = _TOK_LITERAL
-				data = append(data, ch)
+				data = append_char(data, pattern, ix, ch)
 			}
 		case _TOK_RANGE_BEG:
 
-			switch ch {
-
-			case '^':
+			if '^' == ch && 0 == (SuppressRangeNot&flags) {
 
 				state = _TOK_NOT_RANGE
-			default:
 
-				state = _TOK_RANGE
-				data = append(data, ch)
+				continue
 			}
+
+			state = _TOK_RANGE
+
+			fallthrough
 		case _TOK_RANGE, _TOK_NOT_RANGE:
 
-			if ']' == ch && 0 != len(data) {
+			literal_brackets := 0 != (AllowRangeLiteralBracket & flags)
+
+			switch {
+
+			case ']' == ch && ((0 == len(data) && 0 == len(classes)) || (literal_brackets && strings.HasPrefix(pattern[ix+1:], "]"))):
+
+				// ] is literal if it is first or - if AllowRangeLiteralBracket
+				// is specified - immediately precedes the closing ]
+
+				data = append_char(data, pattern, ix, ch)
+				offsets = append(offsets, ix)
+			case ']' == ch:
+
+				if 0 == len(data) && 0 == len(classes) {
+
+					return nil, make_pattern_error(pattern, range_ix, EmptyRange)
+				}
+
+				if err = check_range(pattern, flags, data, offsets); nil != err {
+
+					return nil, err
+				}
 
 				var n node
 
 				switch state {
 
 				case _TOK_RANGE:
-					n = make_range_node(_NODE_RANGE, flags, string(data))
+					n = make_range_node(_NODE_RANGE, flags, string(data), classes)
 
 				case _TOK_NOT_RANGE:
-					n = make_range_node(_NODE_NOT_RANGE, flags, string(data))
+					n = make_range_node(_NODE_NOT_RANGE, flags, string(data), classes)
 				}
 
 				nodes = append(nodes, n)
-				data = make([]rune, 0)
+				data = make([]byte, 0)
+				classes = nil
+				offsets = offsets[:0]
 				state = _TOK_START
-			} else {
+			case '[' == ch && strings.HasPrefix(pattern[ix+1:], ":"):
 
-				data = append(data, ch)
+				var cc char_class
+
+				if cc, skip_to, err = parse_char_class(pattern, flags, ix, data, classes); nil != err {
+
+					return nil, err
+				}
+
+				classes = append(classes, cc)
+			default:
+
+				data = append_char(data, pattern, ix, ch)
+				offsets = append(offsets, ix)
 			}
 		default:
 		}
@@ -310,10 +614,18 @@ func parse_nodes(pattern string, flags uint64) (nodes []node, err error) {
 
 	switch state {
 
-	case _TOK_LITERAL:
+	case _TOK_LITERAL, _TOK_START:
 
-		node := make_node(_NODE_LITERAL, flags, string(data))
-		nodes = append(nodes, node)
+		if bounds.trail {
+
+			data = append(data, byte(separator))
+		}
+
+		if 0 != len(data) {
+
+			node := make_node(_NODE_LITERAL, flags, string(data))
+			nodes = append(nodes, node)
+		}
 	case _TOK_WILD_1:
 
 		node := make_node(_NODE_WILD_1, flags, "")
@@ -322,12 +634,411 @@ func parse_nodes(pattern string, flags uint64) (nodes []node, err error) {
 
 		node := make_node(_NODE_WILD_N, flags, "")
 		nodes = append(nodes, node)
-	}
+	case _TOK_ESCAPED_:
 
-	node := make_node(_NODE_END, flags, "")
-	nodes = append(nodes, node)
+		return nil, make_pattern_error(pattern, escape_ix, TrailingEscape)
+	case _TOK_RANGE_BEG, _TOK_RANGE, _TOK_NOT_RANGE:
+
+		// A range that is unterminated but for a leading literal ] - i.e.
+		// "[]" or "[^]" - was most likely intended to be empty
+
+		if "]" == string(data) && 0 == len(classes) {
+
+			return nil, make_pattern_error(pattern, range_ix, EmptyRange)
+		}
+
+		return nil, make_pattern_error(pattern, range_ix, UnterminatedRange)
+	}
 
 	return
 }
 
+// Checks the characters of a range - data, whose characters are at the
+// given offsets in pattern - against the flags that prohibit certain
+// characters from appearing literally within it, and for ill-formed
+// continua.
+func check_range(pattern string, flags uint64, data []byte, offsets []int) error {
+
+	runes := []rune(string(data))
+
+	if 0 != (SuppressRangeLiteralWildcard & flags) {
+
+		for i, ch := range runes {
+
+			if '?' == ch || '*' == ch {
+
+				return make_pattern_error(pattern, offsets[i], WildcardInRange)
+			}
+		}
+	}
+
+	if 0 != (SuppressRangeContinuumSupport&flags) || 0 == len(runes) {
+
+		return nil
+	}
+
+	if 0 != (SuppressRangeLeadtrailLiteralHyphen & flags) {
+
+		if '-' == runes[0] {
+
+			return make_pattern_error(pattern, offsets[0], LeadtrailHyphenInRange)
+		}
+
+		if '-' == runes[len(runes)-1] {
+
+			return make_pattern_error(pattern, offsets[len(runes)-1], LeadtrailHyphenInRange)
+		}
+	}
+
+	// A hyphen that immediately follows a continuum, other than as the
+	// last character, is ambiguous, as in [a-c-e]
+
+	for i := 0; i+2 < len(runes); i++ {
+
+		if '-' != runes[i+1] || !is_continuum(flags, runes[i], runes[i+2]) {
+
+			continue
+		}
+
+		if i+4 < len(runes) && '-' == runes[i+3] {
+
+			return make_pattern_error(pattern, offsets[i+3], InvalidContinuum)
+		}
+
+		i += 2
+	}
+
+	return nil
+}
+
+// Parses the character class - e.g. "[:alpha:]" - that starts at index ix
+// of pattern, within a range whose characters and classes thus far are
+// data and classes, returning the class and the index of the character
+// that follows it.
+func parse_char_class(pattern string, flags uint64, ix int, data []byte, classes []char_class) (char_class, int, error) {
+
+	n := strings.Index(pattern[ix+2:], ":]")
+
+	if n < 0 {
+
+		return char_class{}, 0, make_pattern_error(pattern, ix, InvalidClass)
+	}
+
+	cc, ok := lookup_char_class(pattern[ix+2 : ix+2+n])
+
+	if !ok {
+
+		return char_class{}, 0, make_pattern_error(pattern, ix, InvalidClass)
+	}
+
+	end := ix + 2 + n + 2
+
+	if 0 == (SuppressRangeContinuumSupport & flags) {
+
+		// A class cannot be the end-point of a continuum, as in [a-[:digit:]]
+		// or [[:digit:]-z], although a hyphen may lead or trail the range
+
+		if '-' == pattern[ix-1] && (1 != len(data) || 0 != len(classes)) {
+
+			return char_class{}, 0, make_pattern_error(pattern, ix-1, InvalidContinuum)
+		}
+
+		if strings.HasPrefix(pattern[end:], "-") && !strings.HasPrefix(pattern[end:], "-]") {
+
+			return char_class{}, 0, make_pattern_error(pattern, end, InvalidContinuum)
+		}
+	}
+
+	return cc, end, nil
+}
+
+// Determines whether the * at index ix of pattern - which is the sequence
+// pattern[beg:], with the given bounds - is the first of a ** that
+// comprises a whole path segment, and PathMode is specified.
+func is_dirs_wildcard(pattern string, beg, ix int, flags uint64, separator rune, bounds sequence_bounds) bool {
+
+	if 0 == (PathMode & flags) {
+
+		return false
+	}
+
+	if !strings.HasPrefix(pattern[ix:], "**") {
+
+		return false
+	}
+
+	if beg == ix {
+
+		if !bounds.at_start {
+
+			return false
+		}
+	} else if separator != rune(pattern[ix-1]) {
+
+		return false
+	}
+
+	if ix+2 == len(pattern) {
+
+		return bounds.at_end
+	}
+
+	return separator == rune(pattern[ix+2])
+}
+
+// Determines whether - in PathMode - any branch of the alternation whose
+// bounds in pattern are alt begins or ends with **, including by way of a
+// nested alternation.
+func has_edge_dirs_wildcard(pattern string, alt []int, flags uint64) bool {
+
+	if 0 == (PathMode & flags) {
+
+		return false
+	}
+
+	for i := 0; i+1 != len(alt); i++ {
+
+		branch := pattern[alt[i]+1 : alt[i+1]]
+
+		if strings.HasPrefix(strings.TrimLeft(branch, "{"), "**") || strings.HasSuffix(strings.TrimRight(branch, "}"), "**") {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// Obtains the bounds of the alternation whose opening brace is at index ix
+// of pattern: the indexes of the opening brace, of each separating comma,
+// and of the closing brace. Commas and braces that are escaped, within a
+// range, or within a nested alternation are not significant. Returns nil
+// if the braces are unbalanced or there is no separating comma - as in
+// "{abc}" - in which case the brace is literal.
+func scan_alternation(pattern string, ix int, flags uint64) []int {
+
+	bounds := []int{ix}
+	depth := 0
+
+	for i := ix + 1; len(pattern) > i; i++ {
+
+		switch pattern[i] {
+
+		case '\\':
+
+			if 0 == (SuppressBackslashEscape & flags) {
+
+				i++
+			}
+		case '[':
+
+			if 0 == (SuppressRangeSupport & flags) {
+
+				if end := scan_range(pattern, i, flags); end >= 0 {
+
+					i = end
+				}
+			}
+		case '{':
+
+			depth++
+		case ',':
+
+			if 0 == depth {
+
+				bounds = append(bounds, i)
+			}
+		case '}':
+
+			if 0 != depth {
+
+				depth--
+
+				continue
+			}
+
+			if 1 == len(bounds) {
+
+				return nil
+			}
+
+			return append(bounds, i)
+		}
+	}
+
+	return nil
+}
+
+// Obtains the index of the closing bracket of the range whose opening
+// bracket is at index ix of pattern, according to the same rules as
+// parse_sequence(), or -1 if the range is unterminated.
+func scan_range(pattern string, ix int, flags uint64) int {
+
+	i := ix + 1
+
+	if strings.HasPrefix(pattern[i:], "^") && 0 == (SuppressRangeNot&flags) {
+
+		i++
+	}
+
+	first := i
+
+	for ; len(pattern) > i; i++ {
+
+		switch {
+
+		case strings.HasPrefix(pattern[i:], "[:"):
+
+			n := strings.Index(pattern[i+2:], ":]")
+
+			if n < 0 {
+
+				return -1
+			}
+
+			i += 2 + n + 1
+		case ']' == pattern[i]:
+
+			if first == i || (0 != (AllowRangeLiteralBracket&flags) && strings.HasPrefix(pattern[i+1:], "]")) {
+
+				continue
+			}
+
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Obtains the quantifier - a sequence of digits and hyphens - from the
+// start of s, which is the remainder of the pattern after a backslash.
+func scan_quantifier(s string) string {
+
+	for ix, ch := range s {
+
+		if '-' != ch && !('0' <= ch && ch <= '9') {
+
+			return s[:ix]
+		}
+	}
+
+	return s
+}
+
+// Parses a quantifier of the form "n", "n-m", "n-", or "-m".
+func parse_quantifier(q string) (min, max int, ok bool) {
+
+	lo, hi, found := strings.Cut(q, "-")
+
+	if strings.Contains(hi, "-") || (0 == len(lo) && 0 == len(hi)) {
+
+		return 0, 0, false
+	}
+
+	min, max = 0, _REPEAT_UNBOUNDED
+
+	if 0 != len(lo) {
+
+		if min, ok = parse_count(lo); !ok {
+
+			return 0, 0, false
+		}
+	}
+
+	switch {
+
+	case !found:
+
+		max = min
+	case 0 != len(hi):
+
+		if max, ok = parse_count(hi); !ok || max < min {
+
+			return 0, 0, false
+		}
+	}
+
+	return min, max, true
+}
+
+func parse_count(s string) (int, bool) {
+
+	n := 0
+
+	for _, ch := range s {
+
+		n = 10*n + int(ch-'0')
+
+		if n > _REPEAT_LIMIT {
+
+			return 0, false
+		}
+	}
+
+	return n, true
+}
+
+// Applies the quantifier q, whose backslash is at index ix of pattern, to
+// the item that precedes it: the last character of the pending literal
+// data, if any; otherwise the last node, if it is a ? or a range.
+func apply_quantifier(pattern string, ix int, q string, nodes []node, data []byte, flags uint64) ([]node, error) {
+
+	min, max, ok := parse_quantifier(q)
+
+	if !ok {
+
+		return nil, make_pattern_error(pattern, ix, InvalidQuantifier)
+	}
+
+	if 0 != len(data) {
+
+		_, w := utf8.DecodeLastRune(data)
+
+		if w != len(data) {
+
+			nodes = append(nodes, make_node(_NODE_LITERAL, flags, string(data[:len(data)-w])))
+		}
+
+		nodes = append(nodes, make_node(_NODE_LITERAL, flags, string(data[len(data)-w:])))
+	} else if 0 != len(nodes) {
+
+		switch n := nodes[len(nodes)-1]; n.node_type {
+
+		case _NODE_WILD_1, _NODE_RANGE, _NODE_NOT_RANGE:
+
+			if n.is_quantified() {
+
+				return nil, make_pattern_error(pattern, ix, InvalidQuantifier)
+			}
+		default:
+
+			return nil, make_pattern_error(pattern, ix, InvalidQuantifier)
+		}
+	} else {
+
+		return nil, make_pattern_error(pattern, ix, InvalidQuantifier)
+	}
+
+	nodes[len(nodes)-1].min = min
+	nodes[len(nodes)-1].max = max
+
+	return nodes, nil
+}
+
+// Appends the character ch, found at index ix of pattern, to data, such
+// that an invalid UTF-8 sequence is retained as its original byte.
+func append_char(data []byte, pattern string, ix int, ch rune) []byte {
+
+	if utf8.RuneError == ch {
+
+		if _, w := utf8.DecodeRuneInString(pattern[ix:]); 1 == w {
+
+			return append(data, pattern[ix])
+		}
+	}
+
+	return utf8.AppendRune(data, ch)
+}
+
 /* ///////////////////////////// end of file //////////////////////////// */
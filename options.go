@@ -0,0 +1,185 @@
+// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2026
+// Matthew Wilson and Synesis Information Systems. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+/*
+ * Created: 18th October 2026
+ * Updated: 18th October 2026
+ */
+
+package shwild
+
+import (
+	"fmt"
+	"strings"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * API types
+ */
+
+// Option is an argument that moderates behaviour - which may be passed,
+// along with flags, to Match(), Compile(), and so on - as obtained from
+// WithIgnoreCase(), WithSeparator(), and so on.
+type Option func(*options)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal types
+ */
+
+// options structure
+//
+// The settings obtained from the arguments that moderate behaviour.
+
+type options struct {
+	flags        uint64
+	separator    rune
+	separator_ix int // the index of the argument that specified separator
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * API functions
+ */
+
+// WithFlags obtains an option that specifies the given flags.
+func WithFlags(flags Flags) Option {
+
+	return func(o *options) {
+
+		o.flags |= uint64(flags)
+	}
+}
+
+// WithIgnoreCase obtains an option that specifies IgnoreCase.
+func WithIgnoreCase() Option {
+
+	return WithFlags(IgnoreCase)
+}
+
+// WithPathMode obtains an option that specifies PathMode.
+func WithPathMode() Option {
+
+	return WithFlags(PathMode)
+}
+
+// WithBraceAlternation obtains an option that specifies
+// AllowBraceAlternation.
+func WithBraceAlternation() Option {
+
+	return WithFlags(AllowBraceAlternation)
+}
+
+// WithExplicitLeadingPeriod obtains an option that specifies
+// ExplicitLeadingPeriod.
+func WithExplicitLeadingPeriod() Option {
+
+	return WithFlags(ExplicitLeadingPeriod)
+}
+
+// WithSeparator obtains an option that specifies the path separator, which
+// is '/' by default, and which has significance only in PathMode. The
+// separator must be a printable ASCII character other than any of "*?[]{}",
+// and may be '\\' only if SuppressBackslashEscape is also specified.
+//
+// The paths of an fs.FS, and of a rule list, are always separated by '/',
+// and so Glob(), NewRuleList(), and so on, accept no other separator.
+func WithSeparator(separator rune) Option {
+
+	return func(o *options) {
+
+		o.separator = separator
+	}
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal functions
+ */
+
+// Parses the arguments that moderate behaviour, each of which may be an
+// Option, a Flags, or a flag value of type int, uint32, or uint64. An
+// invalid argument is reported as an *ArgumentError, and flags that have
+// no name as a *FlagError.
+func parse_options_(args ...any) (options, error) {
+
+	o := options{separator: _PATH_SEPARATOR, separator_ix: -1}
+
+	for i, arg := range args {
+
+		switch v := arg.(type) {
+
+		case Option:
+
+			if nil == v {
+
+				return options{}, &ArgumentError{Index: i, Value: arg, Reason: "nil Option"}
+			}
+
+			separator := o.separator
+
+			v(&o)
+
+			if separator != o.separator {
+
+				o.separator_ix = i
+			}
+
+		case Flags:
+
+			o.flags |= uint64(v)
+
+		case int:
+
+			if v < 0 {
+
+				return options{}, &ArgumentError{Index: i, Value: arg, Reason: fmt.Sprintf("invalid value (%d)", v)}
+			}
+
+			o.flags |= uint64(v)
+
+		case uint32:
+
+			o.flags |= uint64(v)
+
+		case uint64:
+
+			o.flags |= v
+
+		default:
+
+			return options{}, &ArgumentError{Index: i, Value: arg, Reason: fmt.Sprintf("invalid type (%T) for argument '%v'", v, v)}
+		}
+	}
+
+	if _, unknown := names_of_flags(o.flags); 0 != unknown {
+
+		return options{}, &FlagError{Value: unknown}
+	}
+
+	if o.separator <= ' ' || '~' < o.separator || strings.ContainsRune("*?[]{}", o.separator) {
+
+		return options{}, &ArgumentError{Index: o.separator_ix, Value: o.separator, Reason: fmt.Sprintf("invalid separator %q", o.separator)}
+	}
+
+	if '\\' == o.separator && 0 == (SuppressBackslashEscape&o.flags) {
+
+		return options{}, &ArgumentError{Index: o.separator_ix, Value: o.separator, Reason: "separator '\\' requires SuppressBackslashEscape"}
+	}
+
+	return o, nil
+}
+
+// Obtains an *ArgumentError if a separator other than '/' is specified,
+// for paths - of an fs.FS, or of a rule list - that are always separated by
+// '/'.
+func (o options) require_slash_separator() error {
+
+	if _PATH_SEPARATOR != o.separator {
+
+		return &ArgumentError{Index: o.separator_ix, Value: o.separator, Reason: fmt.Sprintf("invalid separator %q: paths are separated by '/'", o.separator)}
+	}
+
+	return nil
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
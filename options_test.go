@@ -0,0 +1,247 @@
+package shwild_test
+
+import (
+	shwild "github.com/synesissoftware/shwild.Go"
+
+	"errors"
+	"testing"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * tests
+ */
+
+func Test_Flags_String(t *testing.T) {
+
+	for _, tc := range []struct {
+		flags    shwild.Flags
+		expected string
+	}{
+		{0, "0"},
+		{shwild.IgnoreCase, "IgnoreCase"},
+		{shwild.PathMode | shwild.IgnoreCase, "IgnoreCase|PathMode"},
+		{shwild.LeftmostLongest | 1<<40, "LeftmostLongest|0x10000000000"},
+	} {
+
+		if actual := tc.flags.String(); tc.expected != actual {
+
+			t.Errorf("String() obtained '%s'; '%s' expected", actual, tc.expected)
+		}
+	}
+}
+
+func Test_ParseFlags(t *testing.T) {
+
+	for _, tc := range []struct {
+		s        string
+		expected shwild.Flags
+	}{
+		{"", 0},
+		{"0", 0},
+		{"IgnoreCase", shwild.IgnoreCase},
+		{"IgnoreCase|PathMode", shwild.IgnoreCase | shwild.PathMode},
+		{" PathMode | AllowBraceAlternation ", shwild.PathMode | shwild.AllowBraceAlternation},
+		{"LeftmostLongest|0x10000000000", shwild.LeftmostLongest | 1<<40},
+		{"0x10000000000|0x20000000000", 3 << 40},
+		{"0x2", shwild.SuppressBackslashEscape},
+	} {
+
+		flags, err := shwild.ParseFlags(tc.s)
+
+		if nil != err || tc.expected != flags {
+
+			t.Errorf("ParseFlags('%s') obtained (%v, %v); %v expected", tc.s, flags, err, tc.expected)
+		}
+
+		if reparsed, _ := shwild.ParseFlags(flags.String()); flags != reparsed {
+
+			t.Errorf("ParseFlags('%s') obtained %v", flags.String(), reparsed)
+		}
+	}
+
+	var fe *shwild.FlagError
+
+	for _, s := range []string{"ignorecase", "IgnoreCase|", "IgnoreCase,PathMode", "0x", "0xg", "0X10", "0x10000000000000000"} {
+
+		if _, err := shwild.ParseFlags(s); !errors.As(err, &fe) {
+
+			t.Errorf("ParseFlags('%s') returned %v; *FlagError expected", s, err)
+		}
+	}
+
+	// an empty term is reported as such
+
+	for _, s := range []string{"IgnoreCase||PathMode", "IgnoreCase|", "|IgnoreCase", "IgnoreCase| |PathMode"} {
+
+		if _, err := shwild.ParseFlags(s); !errors.As(err, &fe) || "" != fe.Name || "empty flag name" != err.Error() {
+
+			t.Errorf("ParseFlags('%s') returned %v; *FlagError for an empty name expected", s, err)
+		}
+	}
+}
+
+func Test_unnamed_flags_are_errors(t *testing.T) {
+
+	for _, args := range [][]any{
+		{uint64(1) << 40},
+		{shwild.IgnoreCase | shwild.Flags(1)<<40},
+		{shwild.PathMode, 1 << 20},
+		{uint32(1) << 31},
+		{shwild.WithFlags(1 << 63)},
+	} {
+
+		var fe *shwild.FlagError
+
+		if _, err := shwild.Match("*", "abc", args...); !errors.As(err, &fe) || 0 == fe.Value {
+
+			t.Errorf("Match() with arguments %v returned %v; *FlagError expected", args, err)
+		}
+
+		if _, err := shwild.Compile("*", args...); !errors.As(err, &fe) || 0 == fe.Value {
+
+			t.Errorf("Compile() with arguments %v returned %v; *FlagError expected", args, err)
+		}
+
+		if _, err := shwild.CompileSet([]string{"*"}, args...); !errors.As(err, &fe) || 0 == fe.Value {
+
+			t.Errorf("CompileSet() with arguments %v returned %v; *FlagError expected", args, err)
+		}
+	}
+
+	if _, err := shwild.Match("*", "abc", shwild.Flags(1)<<40); "unknown flags 0x10000000000" != err.Error() {
+
+		t.Errorf("Match() returned %v", err)
+	}
+}
+
+func Test_ParseFlags_round_trips_String(t *testing.T) {
+
+	for _, flags := range []shwild.Flags{
+		0,
+		shwild.IgnoreCase,
+		shwild.IgnoreCase | shwild.PathMode | shwild.LeftmostLongest,
+		1 << 40,
+		shwild.LeftmostLongest | 1<<40,
+		shwild.IgnoreCase | 1<<40 | 1<<63,
+		^shwild.Flags(0),
+	} {
+
+		s := flags.String()
+
+		if reparsed, err := shwild.ParseFlags(s); nil != err || flags != reparsed {
+
+			t.Errorf("ParseFlags('%s') obtained (%v, %v); %v expected", s, reparsed, err, flags)
+		}
+	}
+}
+
+func Test_Match_with_options(t *testing.T) {
+
+	flags, _ := shwild.ParseFlags("IgnoreCase|PathMode")
+
+	for _, tc := range []struct {
+		pattern  string
+		s        string
+		args     []any
+		expected bool
+	}{
+		{"*.GO", "main.go", []any{shwild.WithIgnoreCase()}, true},
+		{"*.GO", "main.go", []any{flags}, true},
+		{"*.go", "src/main.go", []any{flags}, false},
+		{"*.go", "src/main.go", []any{shwild.WithPathMode()}, false},
+		{"*.{go,md}", "README.md", []any{shwild.WithBraceAlternation()}, true},
+		{"*", ".bashrc", []any{shwild.WithExplicitLeadingPeriod()}, false},
+		{"*.go", "src\\main.go", []any{shwild.WithPathMode(), shwild.WithSeparator('\\'), shwild.SuppressBackslashEscape}, false},
+		{"*.go", "src/main.go", []any{shwild.WithPathMode(), shwild.WithSeparator('\\'), shwild.SuppressBackslashEscape}, true},
+		{"src\\**\\*.go", "src\\a\\b\\main.go", []any{shwild.WithPathMode(), shwild.WithSeparator('\\'), shwild.SuppressBackslashEscape}, true},
+		{"src:**:*.go", "src:main.go", []any{shwild.WithFlags(shwild.PathMode), shwild.WithSeparator(':')}, true},
+		{"src:**:*.go", "src:a:main:x.go", []any{shwild.WithFlags(shwild.PathMode), shwild.WithSeparator(':')}, true},
+		{"src:*.go", "src:a:main.go", []any{shwild.WithFlags(shwild.PathMode), shwild.WithSeparator(':')}, false},
+	} {
+
+		matched, err := shwild.Match(tc.pattern, tc.s, tc.args...)
+
+		if nil != err || tc.expected != matched {
+
+			t.Errorf("Match('%s', '%s') obtained (%v, %v); %v expected", tc.pattern, tc.s, matched, err, tc.expected)
+		}
+
+		cp, _ := shwild.Compile(tc.pattern, tc.args...)
+
+		if matched, _ := cp.Match(tc.s); tc.expected != matched {
+
+			t.Errorf("Compile('%s').Match('%s') obtained %v; %v expected", tc.pattern, tc.s, matched, tc.expected)
+		}
+
+		ps, _ := shwild.CompileSet([]string{tc.pattern}, tc.args...)
+
+		if matched := nil != ps.Match(tc.s); tc.expected != matched {
+
+			t.Errorf("CompileSet('%s').Match('%s') obtained %v; %v expected", tc.pattern, tc.s, matched, tc.expected)
+		}
+	}
+}
+
+func Test_invalid_arguments_are_errors(t *testing.T) {
+
+	var nil_option shwild.Option
+
+	for _, tc := range []struct {
+		args  []any
+		index int
+	}{
+		{[]any{"IgnoreCase"}, 0},
+		{[]any{shwild.IgnoreCase, 1.5}, 1},
+		{[]any{-1}, 0},
+		{[]any{nil}, 0},
+		{[]any{nil_option}, 0},
+		{[]any{shwild.PathMode, shwild.WithSeparator('*')}, 1},
+		{[]any{shwild.WithSeparator('é')}, 0},
+		{[]any{shwild.WithSeparator('\\')}, 0},
+	} {
+
+		var ae *shwild.ArgumentError
+
+		if _, err := shwild.Match("*", "abc", tc.args...); !errors.As(err, &ae) || tc.index != ae.Index {
+
+			t.Errorf("Match() with arguments %v returned %v; *ArgumentError at index %d expected", tc.args, err, tc.index)
+		}
+
+		if _, err := shwild.Compile("*", tc.args...); !errors.As(err, &ae) {
+
+			t.Errorf("Compile() with arguments %v returned %v; *ArgumentError expected", tc.args, err)
+		}
+
+		if _, err := shwild.CompileSet([]string{"*"}, tc.args...); !errors.As(err, &ae) {
+
+			t.Errorf("CompileSet() with arguments %v returned %v; *ArgumentError expected", tc.args, err)
+		}
+
+		if _, err := shwild.NewRuleList([]string{"*.o"}, tc.args...); !errors.As(err, &ae) {
+
+			t.Errorf("NewRuleList() with arguments %v returned %v; *ArgumentError expected", tc.args, err)
+		}
+	}
+}
+
+func Test_path_functions_require_slash_separator(t *testing.T) {
+
+	var ae *shwild.ArgumentError
+
+	if _, err := shwild.Glob(make_test_fs(), "src:*.go", shwild.WithSeparator(':')); !errors.As(err, &ae) {
+
+		t.Errorf("Glob() returned %v; *ArgumentError expected", err)
+	}
+
+	if _, err := shwild.NewRuleList([]string{"*.o"}, shwild.WithSeparator(':')); !errors.As(err, &ae) {
+
+		t.Errorf("NewRuleList() returned %v; *ArgumentError expected", err)
+	}
+
+	if _, err := shwild.Glob(make_test_fs(), "src/*.go", shwild.WithSeparator('/')); nil != err {
+
+		t.Errorf("Glob() failed: %v", err)
+	}
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
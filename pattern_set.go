@@ -0,0 +1,372 @@
+// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2026
+// Matthew Wilson and Synesis Information Systems. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+/*
+ * Created: 18th October 2026
+ * Updated: 18th October 2026
+ */
+
+package shwild
+
+import (
+	"encoding/binary"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * constants
+ */
+
+// The greatest number of states cached by the automaton of a pattern set,
+// beyond which transitions are computed as required.
+const _SET_MAX_DFA_STATES = 10000
+
+/* /////////////////////////////////////////////////////////////////////////
+ * API types
+ */
+
+// PatternSet is a set of patterns, compiled by CompileSet(), against all of
+// which a string may be matched in a single pass. It is safe for
+// concurrent use.
+type PatternSet struct {
+	Patterns []string
+	flags    uint64
+	prog     *program
+	dfa      *set_dfa
+}
+
+// Match matches s against each pattern in the set, and obtains the indices
+// of those that match, in ascending order, or nil if none matches.
+func (ps PatternSet) Match(s string) []int {
+
+	st := ps.match(s)
+
+	if nil == st || 0 == len(st.matches) {
+
+		return nil
+	}
+
+	return slices.Clone(st.matches)
+}
+
+// MatchFirst matches s against each pattern in the set, and obtains the
+// lowest index of those that match.
+func (ps PatternSet) MatchFirst(s string) (int, bool) {
+
+	st := ps.match(s)
+
+	if nil == st || 0 == len(st.matches) {
+
+		return -1, false
+	}
+
+	return st.matches[0], true
+}
+
+func (ps PatternSet) String() string {
+
+	return fmt.Sprintf("<%T{ Patterns=%q }>", ps, ps.Patterns)
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal types
+ */
+
+// set_dfa structure
+//
+// A deterministic automaton, constructed lazily from the program of a
+// pattern set, each of whose states is the set of instructions of the
+// program that are active after some input, so that a string is matched
+// against every pattern with a single transition per character, however
+// many patterns there are. States are shared by all users of the set, and
+// are never discarded.
+
+type set_dfa struct {
+	mu     sync.Mutex
+	states map[string]*dfa_state
+	start  *dfa_state
+}
+
+// dfa_state structure
+//
+// A state of a set_dfa. The transitions on ASCII characters are held in an
+// array, with an additional element for a protected period, and all others
+// in a map.
+
+type dfa_state struct {
+	pcs     []int // the live consuming and match instructions, ascending
+	matches []int // the indices of the patterns matched, ascending
+	ascii   [utf8.RuneSelf + 1]atomic.Pointer[dfa_state]
+	others  sync.Map
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * API functions
+ */
+
+// CompileSet compiles each of patterns, subject to additional arguments
+// that moderate behaviour - which apply to every pattern - into a
+// PatternSet. If any pattern is invalid its error is returned.
+func CompileSet(patterns []string, args ...any) (PatternSet, error) {
+
+	o, err := parse_options_(args...)
+
+	if nil != err {
+
+		return PatternSet{}, err
+	}
+
+	flags := o.flags
+
+	lists := make([][]matcher, len(patterns))
+	size := 0
+	limit := program_limit(patterns...)
+
+	for ix, pattern := range patterns {
+
+		matchers, err := parse_matchers(pattern, flags, o.separator)
+
+		if nil != err {
+
+			return PatternSet{}, err
+		}
+
+		// an empty pattern matches only the empty string
+
+		if 0 == len(matchers) {
+
+			matchers = []matcher{make_end_matcher(flags)}
+		}
+
+		// the patterns together are subject to the limit on program size
+
+		if size += matchers_size(matchers, limit); size > limit {
+
+			return PatternSet{}, make_pattern_error(pattern, 0, PatternTooLarge)
+		}
+
+		lists[ix] = matchers
+	}
+
+	p, entries := compile_set_program(lists, flags, o.separator)
+
+	return PatternSet{
+		Patterns: slices.Clone(patterns),
+		flags:    flags,
+		prog:     p,
+		dfa:      make_set_dfa(p, entries),
+	}, nil
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal functions
+ */
+
+// Obtains the state of the automaton after s, or nil if the set is empty.
+func (ps PatternSet) match(s string) *dfa_state {
+
+	if nil == ps.dfa {
+
+		return nil
+	}
+
+	st := ps.dfa.start
+
+	for i := 0; len(s) != i; {
+
+		if 0 == len(st.pcs) {
+
+			return st
+		}
+
+		c, w := decode_char(s[i:])
+
+		st = ps.dfa.next(ps.prog, st, c, is_protected_period_at(ps.prog, s, i, c))
+		i += w
+	}
+
+	return st
+}
+
+// Compiles the matchers of each pattern into a single program, in which
+// the match instruction of each has as its arg the index of the pattern,
+// and obtains the program and the entry of each pattern.
+func compile_set_program(lists [][]matcher, flags uint64, separator rune) (*program, []int) {
+
+	p := &program{flags: flags, separator: separator}
+
+	entries := make([]int, len(lists))
+
+	for ix, matchers := range lists {
+
+		entries[ix] = len(p.insts)
+
+		for _, m := range matchers {
+
+			p.compile_matcher(m)
+		}
+
+		p.insts[len(p.insts)-1].arg = ix
+	}
+
+	if 0 != (ExplicitLeadingPeriod & flags) {
+
+		split_explicit_paths(p, entries)
+	}
+
+	mark_live_insts(p)
+
+	return p, entries
+}
+
+func make_set_dfa(p *program, entries []int) *set_dfa {
+
+	d := &set_dfa{states: make(map[string]*dfa_state)}
+
+	m := p.get_machine()
+	defer p.put_machine(m)
+
+	m.clist.clear()
+
+	for _, entry := range entries {
+
+		p.add(m, &m.clist, entry)
+	}
+
+	d.start, _ = d.intern(p, m.clist.dense)
+
+	return d
+}
+
+// Obtains the state that follows st on the character c, which is a
+// leading period that must be matched explicitly if protected is true.
+func (d *set_dfa) next(p *program, st *dfa_state, c rune, protected bool) *dfa_state {
+
+	if 0 <= c && c < utf8.RuneSelf {
+
+		slot := &st.ascii[c]
+
+		if protected {
+
+			slot = &st.ascii[utf8.RuneSelf]
+		}
+
+		if nx := slot.Load(); nil != nx {
+
+			return nx
+		}
+
+		nx, cached := d.transition(p, st, c, protected)
+
+		if cached {
+
+			slot.Store(nx)
+		}
+
+		return nx
+	}
+
+	if nx, ok := st.others.Load(c); ok {
+
+		return nx.(*dfa_state)
+	}
+
+	nx, cached := d.transition(p, st, c, protected)
+
+	if cached {
+
+		st.others.Store(c, nx)
+	}
+
+	return nx
+}
+
+// Computes the state that follows st on the character c, and determines
+// whether it is cached.
+func (d *set_dfa) transition(p *program, st *dfa_state, c rune, protected bool) (*dfa_state, bool) {
+
+	m := p.get_machine()
+	defer p.put_machine(m)
+
+	m.nlist.clear()
+
+	for _, pc := range st.pcs {
+
+		if p.consumes(pc, c, protected) {
+
+			p.add(m, &m.nlist, p.insts[pc].out)
+		}
+	}
+
+	return d.intern(p, m.nlist.dense)
+}
+
+// Obtains the state for the set of instructions pcs - of which only those
+// that are live and that consume a character or match are significant -
+// and determines whether it is cached, which it is unless the cache is
+// full.
+func (d *set_dfa) intern(p *program, pcs []int) (*dfa_state, bool) {
+
+	var significant []int
+
+	for _, pc := range pcs {
+
+		if !p.live[pc] {
+
+			continue
+		}
+
+		switch p.insts[pc].op {
+
+		case _OP_CHAR, _OP_ANY, _OP_RANGE, _OP_NOT_RANGE, _OP_MATCH:
+
+			significant = append(significant, pc)
+		}
+	}
+
+	slices.Sort(significant)
+
+	key := make([]byte, 0, 4*len(significant))
+
+	for _, pc := range significant {
+
+		key = binary.LittleEndian.AppendUint32(key, uint32(pc))
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if st, ok := d.states[string(key)]; ok {
+
+		return st, true
+	}
+
+	st := &dfa_state{pcs: significant}
+
+	for _, pc := range significant {
+
+		if i := &p.insts[pc]; _OP_MATCH == i.op {
+
+			st.matches = append(st.matches, i.arg)
+		}
+	}
+
+	slices.Sort(st.matches)
+
+	if len(d.states) >= _SET_MAX_DFA_STATES {
+
+		return st, false
+	}
+
+	d.states[string(key)] = st
+
+	return st, true
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
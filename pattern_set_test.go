@@ -0,0 +1,244 @@
+package shwild_test
+
+import (
+	shwild "github.com/synesissoftware/shwild.Go"
+
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal functions
+ */
+
+// Obtains the indices of the patterns that match s, by matching each
+// separately.
+func match_each(t *testing.T, patterns []string, flags int, s string) []int {
+
+	var indices []int
+
+	for ix, pattern := range patterns {
+
+		matched, err := shwild.Match(pattern, s, flags)
+		if err != nil {
+
+			t.Fatalf("Failed to match pattern '%s': %v", pattern, err)
+		}
+
+		if matched {
+
+			indices = append(indices, ix)
+		}
+	}
+
+	return indices
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * tests
+ */
+
+func Test_PatternSet_Match(t *testing.T) {
+
+	patterns := []string{
+		"*.go",
+		"*_test.go",
+		"README*",
+		"",
+		"*",
+		"[A-Z]*",
+		"main.?o",
+		"*.{md,txt}",
+	}
+
+	ps, err := shwild.CompileSet(patterns, shwild.AllowBraceAlternation)
+	if err != nil {
+
+		t.Fatalf("Failed to compile set: %v", err)
+	}
+
+	for _, tc := range []struct {
+		s        string
+		expected []int
+	}{
+		{"main.go", []int{0, 4, 6}},
+		{"api_test.go", []int{0, 1, 4}},
+		{"README.md", []int{2, 4, 5, 7}},
+		{"", []int{3, 4}},
+		{"x", []int{4}},
+		{"Notes.txt", []int{4, 5, 7}},
+	} {
+
+		if actual := ps.Match(tc.s); !slices.Equal(tc.expected, actual) {
+
+			t.Errorf("Match('%s') returned %v; %v expected", tc.s, actual, tc.expected)
+		}
+
+		first, ok := ps.MatchFirst(tc.s)
+
+		if !ok || tc.expected[0] != first {
+
+			t.Errorf("MatchFirst('%s') returned (%d, %v); (%d, true) expected", tc.s, first, ok, tc.expected[0])
+		}
+	}
+}
+
+func Test_PatternSet_Match_with_no_match(t *testing.T) {
+
+	ps, _ := shwild.CompileSet([]string{"*.go", "a?c"})
+
+	if actual := ps.Match("main.c"); nil != actual {
+
+		t.Errorf("Match() returned %v; nil expected", actual)
+	}
+
+	if first, ok := ps.MatchFirst("main.c"); ok || -1 != first {
+
+		t.Errorf("MatchFirst() returned (%d, %v); (-1, false) expected", first, ok)
+	}
+}
+
+func Test_PatternSet_Match_with_empty_set(t *testing.T) {
+
+	var zero shwild.PatternSet
+
+	ps, _ := shwild.CompileSet(nil)
+
+	for _, s := range []string{"", "abc"} {
+
+		if actual := ps.Match(s); nil != actual {
+
+			t.Errorf("Match('%s') returned %v; nil expected", s, actual)
+		}
+
+		if actual := zero.Match(s); nil != actual {
+
+			t.Errorf("Match('%s') of zero set returned %v; nil expected", s, actual)
+		}
+	}
+}
+
+func Test_PatternSet_Match_with_path_flags(t *testing.T) {
+
+	patterns := []string{
+		"*.go",
+		"src/*.go",
+		"src/**/*.go",
+		"**/.git/**",
+		".*",
+		"*/.*",
+	}
+
+	flags := shwild.PathMode | shwild.ExplicitLeadingPeriod
+
+	ps, _ := shwild.CompileSet(patterns, flags)
+
+	for _, s := range []string{
+		"main.go",
+		".main.go",
+		"src/main.go",
+		"src/a/b/main.go",
+		"src/.a/main.go",
+		".git/config",
+		"a/.git/config",
+		"a/.profile",
+		".profile",
+	} {
+
+		if expected, actual := match_each(t, patterns, flags, s), ps.Match(s); !slices.Equal(expected, actual) {
+
+			t.Errorf("Match('%s') returned %v; %v expected", s, actual, expected)
+		}
+	}
+}
+
+func Test_PatternSet_Match_agrees_with_Match_randomly(t *testing.T) {
+
+	tokens := []string{"a", "b", ".", "/", "é", "?", "*", "[ab]", "[!a]", "**"}
+
+	rng := rand.New(rand.NewSource(18))
+
+	for _, flags := range []int{0, shwild.IgnoreCase, shwild.PathMode | shwild.ExplicitLeadingPeriod} {
+
+		patterns := make([]string, 50)
+
+		for ix := range patterns {
+
+			for n := rng.Intn(5); 0 != n; n-- {
+
+				patterns[ix] += tokens[rng.Intn(len(tokens))]
+			}
+		}
+
+		ps, err := shwild.CompileSet(patterns, flags)
+		if err != nil {
+
+			t.Fatalf("Failed to compile set: %v", err)
+		}
+
+		for n := 0; 500 != n; n++ {
+
+			var s string
+
+			for l := rng.Intn(7); 0 != l; l-- {
+
+				s += []string{"a", "A", "b", ".", "/", "é", "É"}[rng.Intn(7)]
+			}
+
+			if expected, actual := match_each(t, patterns, flags, s), ps.Match(s); !slices.Equal(expected, actual) {
+
+				t.Fatalf("with flags 0x%x, Match('%s') returned %v; %v expected", flags, s, actual, expected)
+			}
+		}
+	}
+}
+
+func Test_CompileSet_with_invalid_pattern(t *testing.T) {
+
+	_, err := shwild.CompileSet([]string{"*.go", "[abc"})
+
+	var pe *shwild.PatternError
+
+	if !errors.As(err, &pe) || "[abc" != pe.Pattern {
+
+		t.Errorf("CompileSet() returned %v; error for pattern '[abc' expected", err)
+	}
+}
+
+func Test_CompileSet_with_patterns_too_large(t *testing.T) {
+
+	// each pattern is within the limit, but together they are not
+
+	patterns := make([]string, 20)
+
+	for i := range patterns {
+
+		patterns[i] = strings.Repeat("[a-z]\\0-1000", 10)
+	}
+
+	if _, err := shwild.CompileSet(patterns, shwild.AllowRangeQuantification); !errors.Is(err, shwild.PatternTooLarge) {
+
+		t.Errorf("CompileSet() returned %v; %v expected", err, shwild.PatternTooLarge)
+	}
+
+	if _, err := shwild.CompileSet(patterns[:2], shwild.AllowRangeQuantification); nil != err {
+
+		t.Errorf("CompileSet() failed: %v", err)
+	}
+}
+
+func Test_PatternSet_String(t *testing.T) {
+
+	ps, _ := shwild.CompileSet([]string{"*.go", "a"})
+
+	if expected, actual := fmt.Sprintf("<%T{ Patterns=[\"*.go\" \"a\"] }>", ps), ps.String(); expected != actual {
+
+		t.Errorf("String() returned '%s'; '%s' expected", actual, expected)
+	}
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
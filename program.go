@@ -0,0 +1,1318 @@
+// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2026
+// Matthew Wilson and Synesis Information Systems. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+/*
+ * Created: 18th October 2026
+ * Updated: 18th October 2026
+ */
+
+package shwild
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"unicode/utf8"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal types
+ */
+
+// _OpCode enumeration
+
+type _OpCode int
+
+const (
+	_OP_CHAR      _OpCode = iota // consumes a character equal to r
+	_OP_ANY                      // consumes any character
+	_OP_RANGE                    // consumes a character in the range n
+	_OP_NOT_RANGE                // consumes a character not in the range n
+	_OP_SPLIT                    // proceeds to both out and arg
+	_OP_JUMP                     // proceeds to out
+	_OP_SAVE                     // records the position in capture slot arg
+	_OP_MATCH                    // the pattern, of index arg in a set, is matched
+)
+
+func (op _OpCode) String() string {
+
+	switch op {
+
+	case _OP_CHAR:
+		return "_OP_CHAR"
+	case _OP_ANY:
+		return "_OP_ANY"
+	case _OP_RANGE:
+		return "_OP_RANGE"
+	case _OP_NOT_RANGE:
+		return "_OP_NOT_RANGE"
+	case _OP_SPLIT:
+		return "_OP_SPLIT"
+	case _OP_JUMP:
+		return "_OP_JUMP"
+	case _OP_SAVE:
+		return "_OP_SAVE"
+	case _OP_MATCH:
+		return "_OP_MATCH"
+	}
+
+	return fmt.Sprintf("<%T %d>", op, op)
+}
+
+// inst structure
+
+type inst struct {
+	op       _OpCode
+	flags    uint64
+	r        rune
+	n        node
+	out      int
+	arg      int
+	explicit bool // a character at the start of a segment of the pattern
+}
+
+func (i inst) String() string {
+
+	return fmt.Sprintf("<%T{ op=%v, r=%q, out=%d, arg=%d }>", i, i.op, i.r, i.out, i.arg)
+}
+
+// program structure
+//
+// A program is a non-deterministic finite automaton, in the manner of
+// Thompson, that is simulated - one character of the input at a time -
+// over the set of all states that may be active, and so matches in
+// O(len(pattern) * len(s)) time with no backtracking.
+//
+// A program that consists only of single-character instructions and
+// stars - which is the usual case - is marked as simple, and is instead
+// matched by the classic greedy-star algorithm that backtracks only to the
+// most recent star, which has the same bound but requires no state sets.
+//
+// A capturing program additionally has instructions that record the
+// positions at which each wildcard begins and ends its match (see
+// program.match_captures()). It is compiled - from the same matchers -
+// only when captures are first requested.
+
+type program struct {
+	insts     []inst
+	flags     uint64
+	separator rune
+	simple    bool
+	machines  sync.Pool
+	live      []bool // whether a match is reachable from each instruction
+	matchers  []matcher
+	capturing bool
+	ncap      int // the number of wildcards captured
+	captures  struct {
+		once sync.Once
+		p    *program
+	}
+}
+
+// machine structure
+//
+// The working state of a single simulation of a program, which is pooled
+// to avoid allocation on each match.
+
+type machine struct {
+	clist state_set
+	nlist state_set
+	stack []int
+}
+
+func (p *program) get_machine() *machine {
+
+	if m, ok := p.machines.Get().(*machine); ok {
+
+		return m
+	}
+
+	n := len(p.insts)
+
+	return &machine{
+		clist: make_state_set(n),
+		nlist: make_state_set(n),
+		stack: make([]int, 0, n),
+	}
+}
+
+func (p *program) put_machine(m *machine) {
+
+	p.machines.Put(m)
+}
+
+// Appends an instruction, whose out will be the next instruction, and
+// returns its index.
+func (p *program) emit(i inst) int {
+
+	pc := len(p.insts)
+
+	i.out = pc + 1
+
+	p.insts = append(p.insts, i)
+
+	return pc
+}
+
+// Determines whether the instruction at pc consumes the character c,
+// which is a leading period that must be matched explicitly if protected
+// is true.
+func (p *program) consumes(pc int, c rune, protected bool) bool {
+
+	i := &p.insts[pc]
+
+	if protected && !i.explicit {
+
+		return false
+	}
+
+	switch i.op {
+
+	case _OP_CHAR:
+
+		if 0 != (IgnoreCase & i.flags) {
+
+			return fold_equal(i.r, c)
+		}
+
+		return i.r == c
+	case _OP_ANY:
+
+		return !p.is_path_separator(i.flags, c)
+	case _OP_RANGE:
+
+		return !p.is_path_separator(i.flags, c) && range_contains(i.n, c)
+	case _OP_NOT_RANGE:
+
+		return !p.is_path_separator(i.flags, c) && !range_contains(i.n, c)
+	}
+
+	return false
+}
+
+func (p *program) match(s string) bool {
+
+	return match_input(p, s)
+}
+
+// Matches the subject s, which is a string or a byte slice, against the
+// program, without conversion.
+func match_input[S input](p *program, s S) bool {
+
+	if p.simple {
+
+		return match_simple(p, s)
+	}
+
+	m := p.get_machine()
+	defer p.put_machine(m)
+
+	p.start(m)
+
+	for i := 0; len(s) != i; {
+
+		c, w := decode_char(s[i:])
+
+		if !p.step(m, c, is_protected_period_at(p, s, i, c)) {
+
+			return false
+		}
+
+		i += w
+	}
+
+	return p.accepts(m)
+}
+
+// Matches the characters rs against the program.
+func (p *program) match_runes(rs []rune) bool {
+
+	m := p.get_machine()
+	defer p.put_machine(m)
+
+	p.start(m)
+
+	for i, c := range rs {
+
+		prev := rune(0)
+
+		if 0 != i {
+
+			prev = rs[i-1]
+		}
+
+		if !p.step(m, c, p.is_protected_period(c, prev, 0 == i)) {
+
+			return false
+		}
+	}
+
+	return p.accepts(m)
+}
+
+// Matches the characters read from rr against the program, reading no
+// further once no match is possible.
+func (p *program) match_reader(rr io.RuneReader) (bool, error) {
+
+	m := p.get_machine()
+	defer p.put_machine(m)
+
+	p.start(m)
+
+	for prev, first := rune(0), true; ; first = false {
+
+		c, _, err := rr.ReadRune()
+
+		if nil != err {
+
+			if io.EOF == err {
+
+				return p.accepts(m), nil
+			}
+
+			return false, err
+		}
+
+		if !p.step(m, c, p.is_protected_period(c, prev, first)) {
+
+			return false, nil
+		}
+
+		prev = c
+	}
+}
+
+// Matches the prefix s against the program, determining whether s
+// matches, or whether some extension of it may yet match.
+func (p *program) match_partial(s string) PartialResult {
+
+	m := p.get_machine()
+	defer p.put_machine(m)
+
+	p.start(m)
+
+	for i := 0; len(s) != i; {
+
+		c, w := decode_char(s[i:])
+
+		if !p.step(m, c, is_protected_period_at(p, s, i, c)) {
+
+			return NoMatch
+		}
+
+		i += w
+	}
+
+	result := NoMatch
+
+	for _, pc := range m.clist.dense {
+
+		if _OP_MATCH == p.insts[pc].op {
+
+			return Matched
+		}
+
+		if p.live[pc] {
+
+			result = NeedMore
+		}
+	}
+
+	return result
+}
+
+func match_simple[S input](p *program, s S) bool {
+
+	pc, si := 0, 0
+	star_pc, star_si := -1, 0
+
+	for {
+
+		i := &p.insts[pc]
+
+		switch i.op {
+
+		case _OP_SPLIT:
+
+			// a star, which initially matches nothing
+
+			star_pc, star_si = pc, si
+			pc = i.arg
+
+			continue
+		case _OP_MATCH:
+
+			if len(s) == si {
+
+				return true
+			}
+		default:
+
+			if len(s) != si {
+
+				c, w := decode_char(s[si:])
+
+				if p.consumes(pc, c, is_protected_period_at(p, s, si, c)) {
+
+					pc = i.out
+					si += w
+
+					continue
+				}
+			}
+		}
+
+		// the most recent star, if any, consumes one more character. If it
+		// cannot - because the character is the path separator or a
+		// protected period - then neither can any earlier star, since the
+		// characters between them include the same character
+
+		if star_pc < 0 || len(s) == star_si {
+
+			return false
+		}
+
+		c, w := decode_char(s[star_si:])
+
+		if !p.consumes(star_pc+1, c, is_protected_period_at(p, s, star_si, c)) {
+
+			return false
+		}
+
+		star_si += w
+		pc, si = p.insts[star_pc].arg, star_si
+	}
+}
+
+// Begins a simulation of the program, in which the current states are
+// those reachable from the first instruction.
+func (p *program) start(m *machine) {
+
+	m.clist.clear()
+	p.add(m, &m.clist, 0)
+}
+
+// Advances the simulation by the character c, which is a leading period
+// that must be matched explicitly if protected is true, and determines
+// whether any states remain.
+func (p *program) step(m *machine, c rune, protected bool) bool {
+
+	m.nlist.clear()
+
+	for _, pc := range m.clist.dense {
+
+		if p.consumes(pc, c, protected) {
+
+			p.add(m, &m.nlist, p.insts[pc].out)
+		}
+	}
+
+	m.clist, m.nlist = m.nlist, m.clist
+
+	return 0 != len(m.clist.dense)
+}
+
+// Determines whether the simulation is in a matching state.
+func (p *program) accepts(m *machine) bool {
+
+	for _, pc := range m.clist.dense {
+
+		if _OP_MATCH == p.insts[pc].op {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// Determines whether the character c, which follows prev or, if first is
+// true, begins the subject, is a period that may be matched only
+// explicitly, because ExplicitLeadingPeriod is specified and it begins the
+// subject or, in path mode, follows a separator.
+func (p *program) is_protected_period(c, prev rune, first bool) bool {
+
+	if '.' != c || 0 == (ExplicitLeadingPeriod&p.flags) {
+
+		return false
+	}
+
+	return first || (0 != (PathMode&p.flags) && p.separator == prev)
+}
+
+// Determines whether the character c, at index i of s, is a period that
+// may be matched only explicitly (see program.is_protected_period()).
+func is_protected_period_at[S input](p *program, s S, i int, c rune) bool {
+
+	if 0 == i {
+
+		return p.is_protected_period(c, 0, true)
+	}
+
+	return p.is_protected_period(c, rune(s[i-1]), false)
+}
+
+// Adds pc, and all states reachable from it without consuming a
+// character, to set.
+func (p *program) add(m *machine, set *state_set, pc int) {
+
+	stack := append(m.stack[:0], pc)
+
+	for 0 != len(stack) {
+
+		pc = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if set.contains(pc) {
+
+			continue
+		}
+
+		set.insert(pc)
+
+		switch i := &p.insts[pc]; i.op {
+
+		case _OP_SPLIT:
+
+			// push arg first, so that out is followed first
+
+			stack = append(stack, i.arg, i.out)
+		case _OP_JUMP, _OP_SAVE:
+
+			stack = append(stack, i.out)
+		}
+	}
+
+	m.stack = stack
+}
+
+// Obtains the capturing program that corresponds to p.
+func (p *program) capturing_program() *program {
+
+	if p.capturing {
+
+		return p
+	}
+
+	p.captures.once.Do(func() {
+
+		p.captures.p = compile_program_(p.matchers, p.flags, p.separator, true)
+	})
+
+	return p.captures.p
+}
+
+// Emits the instructions that implement the matcher m, bracketed - if the
+// program is capturing and m is a wildcard - by instructions that record
+// the positions at which m begins and ends its match.
+func (p *program) compile_matcher(m matcher) {
+
+	if !p.capturing || !is_wildcard_matcher(m) {
+
+		m.compile(p)
+
+		return
+	}
+
+	slot := 2 + 2*p.ncap
+
+	p.ncap++
+
+	p.emit(inst{op: _OP_SAVE, flags: p.flags, arg: slot})
+	m.compile(p)
+	p.emit(inst{op: _OP_SAVE, flags: p.flags, arg: slot + 1})
+}
+
+// thread structure
+//
+// A state of the simulation of a program in a search, with the position
+// at which its match began.
+
+type thread struct {
+	pc    int
+	start int
+}
+
+// Matches s against the capturing program, obtaining the byte positions
+// of the whole match - in caps[0] and caps[1] - and of each wildcard - in
+// caps[2*n] and caps[2*n+1] - or -1 for a wildcard that does not
+// participate in the match. Where the match is ambiguous, stars are
+// greedy and alternation branches are preferred in order.
+//
+// Rather than simulating threads that each carry their own captures -
+// which would cost O(len(pattern)) for each thread at each character - the
+// match is made in two passes, each in O(len(pattern) * len(s)) time: the
+// first determines, backwards from the end of s, the states from which the
+// remainder of s may be matched; the second follows, forwards from the
+// start, the path of highest priority through only those states, and
+// records the captures along it.
+func (p *program) match_captures(s string) (caps []int, matched bool) {
+
+	// the positions of the characters of s, and of its end
+
+	offsets := make([]int, 0, len(s)+1)
+
+	for i := 0; len(s) != i; {
+
+		offsets = append(offsets, i)
+
+		_, w := decode_char(s[i:])
+
+		i += w
+	}
+
+	offsets = append(offsets, len(s))
+
+	viable := p.viable_states(s, offsets)
+
+	if !viable[0].contains(0) {
+
+		return nil, false
+	}
+
+	caps = make([]int, 2+2*p.ncap)
+
+	for ix := range caps {
+
+		caps[ix] = -1
+	}
+
+	caps[0], caps[1] = 0, len(s)
+
+	visited := make_state_set(len(p.insts))
+
+	var saves []int
+
+	for k, pc := 0, 0; len(offsets) != k; k++ {
+
+		visited.clear()
+		saves = saves[:0]
+
+		pc, _ = p.follow(viable[k], &visited, &saves, pc)
+
+		for _, save := range saves {
+
+			caps[p.insts[save].arg] = offsets[k]
+		}
+
+		if _OP_MATCH != p.insts[pc].op {
+
+			pc = p.insts[pc].out
+		}
+	}
+
+	return caps, true
+}
+
+// Obtains, for each of offsets - the positions of the characters of s,
+// and of its end - the set of states from which the remainder of s may be
+// matched, which are those that consume the character there and proceed
+// to such a state for the next, or are the match instruction at the end,
+// along with all those from which they are reachable without consuming a
+// character.
+func (p *program) viable_states(s string, offsets []int) []state_bits {
+
+	n := len(p.insts)
+
+	// the instructions from which each may be reached without consuming a
+	// character, those of pc being from[ends[pc]:ends[pc+1]]
+
+	ends := make([]int, n+1)
+
+	for _, i := range p.insts {
+
+		switch i.op {
+
+		case _OP_SPLIT:
+
+			ends[i.out+1]++
+			ends[i.arg+1]++
+		case _OP_JUMP, _OP_SAVE:
+
+			ends[i.out+1]++
+		}
+	}
+
+	for pc := 0; n != pc; pc++ {
+
+		ends[pc+1] += ends[pc]
+	}
+
+	from := make([]int, ends[n])
+	next := slices.Clone(ends)
+
+	for pc, i := range p.insts {
+
+		switch i.op {
+
+		case _OP_SPLIT:
+
+			from[next[i.out]], next[i.out] = pc, next[i.out]+1
+			from[next[i.arg]], next[i.arg] = pc, next[i.arg]+1
+		case _OP_JUMP, _OP_SAVE:
+
+			from[next[i.out]], next[i.out] = pc, next[i.out]+1
+		}
+	}
+
+	words := (n + 63) / 64
+	bits := make(state_bits, len(offsets)*words)
+	viable := make([]state_bits, len(offsets))
+
+	var stack []int
+
+	for k := len(offsets) - 1; k >= 0; k-- {
+
+		viable[k] = bits[k*words : (k+1)*words]
+
+		last := len(offsets)-1 == k
+
+		var c rune
+		var protected bool
+
+		if !last {
+
+			c, _ = decode_char(s[offsets[k]:])
+			protected = is_protected_period_at(p, s, offsets[k], c)
+		}
+
+		for pc, i := range p.insts {
+
+			switch i.op {
+
+			case _OP_MATCH:
+
+				if last {
+
+					stack = append(stack, pc)
+				}
+			case _OP_CHAR, _OP_ANY, _OP_RANGE, _OP_NOT_RANGE:
+
+				if !last && viable[k+1].contains(i.out) && p.consumes(pc, c, protected) {
+
+					stack = append(stack, pc)
+				}
+			}
+		}
+
+		for 0 != len(stack) {
+
+			pc := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if viable[k].contains(pc) {
+
+				continue
+			}
+
+			viable[k].insert(pc)
+
+			stack = append(stack, from[ends[pc]:ends[pc+1]]...)
+		}
+	}
+
+	return viable
+}
+
+// Follows, in order of priority, the states reachable from pc without
+// consuming a character - through only those that are viable - to the
+// first that consumes a character or is the match instruction, which it
+// obtains, appending to saves each capture instruction along the way.
+func (p *program) follow(viable state_bits, visited *state_set, saves *[]int, pc int) (int, bool) {
+
+	if visited.contains(pc) || !viable.contains(pc) {
+
+		return 0, false
+	}
+
+	visited.insert(pc)
+
+	switch i := &p.insts[pc]; i.op {
+
+	case _OP_SPLIT:
+
+		if to, ok := p.follow(viable, visited, saves, i.out); ok {
+
+			return to, true
+		}
+
+		return p.follow(viable, visited, saves, i.arg)
+	case _OP_JUMP:
+
+		return p.follow(viable, visited, saves, i.out)
+	case _OP_SAVE:
+
+		n := len(*saves)
+
+		*saves = append(*saves, pc)
+
+		if to, ok := p.follow(viable, visited, saves, i.out); ok {
+
+			return to, true
+		}
+
+		*saves = (*saves)[:n]
+
+		return 0, false
+	}
+
+	return pc, true
+}
+
+// Searches s, from index from, for the leftmost substring that matches the
+// program, obtaining the shortest - or, if longest is true, the longest -
+// such substring.
+//
+// Each thread records the position at which it began, and new threads
+// are begun - at lower priority than existing ones - at each position
+// until a match is found, so that the list of threads is always ordered
+// by ascending start position.
+func (p *program) search(s string, from int, longest bool) (start, end int, ok bool) {
+
+	visited := make_state_set(len(p.insts))
+
+	clist := p.add_thread(nil, &visited, 0, from)
+
+	var nlist []thread
+
+	for i := from; ; {
+
+		// the first matching thread has the leftmost start
+
+		for _, t := range clist {
+
+			if _OP_MATCH != p.insts[t.pc].op {
+
+				continue
+			}
+
+			switch st := t.start; {
+
+			case !ok || st < start:
+
+				start, end, ok = st, i, true
+			case longest && st == start:
+
+				end = i
+			}
+
+			break
+		}
+
+		// only threads that may yet improve on the match are retained
+
+		if ok {
+
+			retained := clist[:0]
+
+			for _, t := range clist {
+
+				if t.start < start || (longest && t.start == start) {
+
+					retained = append(retained, t)
+				}
+			}
+
+			clist = retained
+		}
+
+		if len(s) == i || (ok && 0 == len(clist)) {
+
+			return
+		}
+
+		c, w := decode_char(s[i:])
+		protected := is_protected_period_at(p, s, i, c)
+
+		visited.clear()
+		nlist = nlist[:0]
+
+		for _, t := range clist {
+
+			if p.consumes(t.pc, c, protected) {
+
+				nlist = p.add_thread(nlist, &visited, p.insts[t.pc].out, t.start)
+			}
+		}
+
+		i += w
+
+		if !ok {
+
+			nlist = p.add_thread(nlist, &visited, 0, i)
+		}
+
+		clist, nlist = nlist, clist
+	}
+}
+
+// Appends, in order of priority, the thread at pc - and all those
+// reachable from it without consuming a character - to list, each with
+// the position start at which its match began.
+func (p *program) add_thread(list []thread, visited *state_set, pc, start int) []thread {
+
+	if visited.contains(pc) {
+
+		return list
+	}
+
+	visited.insert(pc)
+
+	switch i := &p.insts[pc]; i.op {
+
+	case _OP_SPLIT:
+
+		list = p.add_thread(list, visited, i.out, start)
+		list = p.add_thread(list, visited, i.arg, start)
+	case _OP_JUMP, _OP_SAVE:
+
+		list = p.add_thread(list, visited, i.out, start)
+	default:
+
+		list = append(list, thread{pc: pc, start: start})
+	}
+
+	return list
+}
+
+// input constraint
+//
+// The types of subject that may be matched by index without conversion.
+
+type input interface {
+	~string | ~[]byte
+}
+
+// state_set structure
+//
+// A sparse set of program counters, with O(1) insertion, membership, and
+// clearing, in the manner of Briggs & Torczon.
+
+type state_set struct {
+	sparse []int
+	dense  []int
+}
+
+func make_state_set(n int) state_set {
+
+	return state_set{
+		sparse: make([]int, n),
+		dense:  make([]int, 0, n),
+	}
+}
+
+func (set *state_set) contains(pc int) bool {
+
+	ix := set.sparse[pc]
+
+	return ix < len(set.dense) && pc == set.dense[ix]
+}
+
+func (set *state_set) insert(pc int) {
+
+	set.sparse[pc] = len(set.dense)
+	set.dense = append(set.dense, pc)
+}
+
+func (set *state_set) clear() {
+
+	set.dense = set.dense[:0]
+}
+
+// state_bits structure
+//
+// A dense set of program counters, of one bit each.
+
+type state_bits []uint64
+
+func (bits state_bits) contains(pc int) bool {
+
+	return 0 != bits[pc/64]&(1<<(pc%64))
+}
+
+func (bits state_bits) insert(pc int) {
+
+	bits[pc/64] |= 1 << (pc % 64)
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal functions
+ */
+
+// Decodes the first character of s, returning it and its encoded length.
+//
+// An invalid UTF-8 sequence is decoded as a single character of one byte,
+// represented by a negative value unique to that byte, so that it is
+// distinct from every valid character (including utf8.RuneError) and is
+// equal only to the same invalid byte in a pattern literal.
+func decode_char[S input](s S) (rune, int) {
+
+	if s[0] < utf8.RuneSelf {
+
+		return rune(s[0]), 1
+	}
+
+	// copy the (at most) one encoded character, so that a string need not
+	// be converted
+
+	var buf [utf8.UTFMax]byte
+
+	n := copy(buf[:], s)
+
+	r, w := utf8.DecodeRune(buf[:n])
+
+	if utf8.RuneError == r && 1 == w {
+
+		return -1 - rune(s[0]), 1
+	}
+
+	return r, w
+}
+
+// Determines whether c is the path separator, and PathMode is specified.
+func (p *program) is_path_separator(flags uint64, c rune) bool {
+
+	return p.separator == c && 0 != (PathMode&flags)
+}
+
+func compile_program(matchers []matcher, flags uint64, separator rune) *program {
+
+	return compile_program_(matchers, flags, separator, false)
+}
+
+func compile_program_(matchers []matcher, flags uint64, separator rune, capturing bool) *program {
+
+	p := &program{flags: flags, separator: separator, matchers: matchers, capturing: capturing}
+
+	for _, m := range matchers {
+
+		p.compile_matcher(m)
+	}
+
+	if 0 != (ExplicitLeadingPeriod & flags) {
+
+		split_explicit_paths(p, []int{0})
+	}
+
+	mark_live_insts(p)
+
+	p.simple = is_simple_program(p)
+
+	return p
+}
+
+// Marks as live each instruction from which the match instruction may be
+// reached, by way of instructions each of which may consume some
+// character - which excludes, for example, a range that contains only the
+// separator, in path mode.
+func mark_live_insts(p *program) {
+
+	p.live = make([]bool, len(p.insts))
+
+	for changed := true; changed; {
+
+		changed = false
+
+		for pc := len(p.insts) - 1; pc >= 0; pc-- {
+
+			if p.live[pc] {
+
+				continue
+			}
+
+			i := &p.insts[pc]
+
+			var live bool
+
+			switch i.op {
+
+			case _OP_MATCH:
+
+				live = true
+			case _OP_SPLIT:
+
+				live = p.live[i.out] || p.live[i.arg]
+			case _OP_JUMP, _OP_SAVE:
+
+				live = p.live[i.out]
+			case _OP_RANGE:
+
+				live = p.live[i.out] && is_range_viable(p, i.n)
+			default:
+
+				live = p.live[i.out]
+			}
+
+			if live {
+
+				p.live[pc] = true
+				changed = true
+			}
+		}
+	}
+}
+
+// Determines whether the range may contain some character, which in path
+// mode must be other than the separator.
+func is_range_viable(p *program, n node) bool {
+
+	if 0 != len(n.classes) {
+
+		return true
+	}
+
+	for _, iv := range n.runes.intervals {
+
+		if iv.lo != iv.hi || !p.is_path_separator(n.flags, iv.lo) {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// Marks as explicit each instruction that matches a period at the start of
+// the pattern or - in path mode - after a separator, other than by way of a
+// wildcard (even one that matches nothing), and so may match a protected
+// period. The program starts at each of entries.
+//
+// An instruction that may be reached both in this way and otherwise - as
+// may the period in "{*,x/}.b" - is explicit only along the former paths,
+// and so it, and the instructions that reach it without consuming a
+// character, are duplicated: the originals are reached only along the
+// former paths, and the duplicates along the others.
+func split_explicit_paths(p *program, entries []int) {
+
+	n := len(p.insts)
+
+	is_star := func(pc int) bool {
+
+		i := &p.insts[pc]
+
+		if _OP_SPLIT != i.op {
+
+			return false
+		}
+
+		any := &p.insts[i.out]
+
+		return _OP_ANY == any.op && pc == any.out
+	}
+
+	is_separator := func(pc int) bool {
+
+		i := &p.insts[pc]
+
+		return _OP_CHAR == i.op && p.separator == i.r && 0 != (PathMode&p.flags)
+	}
+
+	// the instructions that may be reached explicitly, and those that may
+	// be reached otherwise
+
+	explicit := make([]bool, n)
+	implicit := make([]bool, n)
+
+	var stack []int
+
+	reach := func(reached []bool, pcs ...int) {
+
+		stack = append(stack[:0], pcs...)
+
+		for 0 != len(stack) {
+
+			pc := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if reached[pc] {
+
+				continue
+			}
+
+			reached[pc] = true
+
+			switch i := &p.insts[pc]; i.op {
+
+			case _OP_SPLIT:
+
+				if !is_star(pc) {
+
+					stack = append(stack, i.out, i.arg)
+				}
+			case _OP_JUMP, _OP_SAVE:
+
+				stack = append(stack, i.out)
+			}
+		}
+	}
+
+	reach(explicit, entries...)
+
+	for pc := range p.insts {
+
+		switch i := &p.insts[pc]; i.op {
+
+		case _OP_CHAR, _OP_ANY, _OP_RANGE, _OP_NOT_RANGE:
+
+			if is_separator(pc) {
+
+				reach(explicit, i.out)
+			} else {
+
+				reach(implicit, i.out)
+			}
+		case _OP_SPLIT:
+
+			if is_star(pc) {
+
+				reach(implicit, i.out, i.arg)
+			}
+		}
+	}
+
+	// the instructions that may match a period without first consuming a
+	// character, and so behave differently when reached explicitly
+
+	sensitive := make([]bool, n)
+
+	for changed := true; changed; {
+
+		changed = false
+
+		for pc := n - 1; pc >= 0; pc-- {
+
+			if sensitive[pc] {
+
+				continue
+			}
+
+			switch i := &p.insts[pc]; i.op {
+
+			case _OP_CHAR:
+
+				sensitive[pc] = '.' == i.r
+			case _OP_SPLIT:
+
+				sensitive[pc] = !is_star(pc) && (sensitive[i.out] || sensitive[i.arg])
+			case _OP_JUMP, _OP_SAVE:
+
+				sensitive[pc] = sensitive[i.out]
+			}
+
+			changed = changed || sensitive[pc]
+		}
+	}
+
+	// duplicate each sensitive instruction that may be reached both
+	// explicitly and otherwise
+
+	twins := make([]int, n)
+
+	for pc := range p.insts {
+
+		twins[pc] = -1
+
+		if sensitive[pc] && explicit[pc] && implicit[pc] {
+
+			twins[pc] = len(p.insts)
+
+			p.insts = append(p.insts, p.insts[pc])
+		}
+	}
+
+	// direct to the duplicate each reference that is made other than
+	// explicitly: by a character other than the separator, by a star, or
+	// by a duplicate or an instruction that is reached only otherwise
+
+	for pc := range p.insts {
+
+		i := &p.insts[pc]
+
+		var implicitly bool
+
+		switch {
+
+		case pc >= n:
+
+			implicitly = _OP_CHAR != i.op || !is_separator(pc)
+		case _OP_SPLIT == i.op && is_star(pc):
+
+			implicitly = true
+		case _OP_SPLIT == i.op, _OP_JUMP == i.op, _OP_SAVE == i.op:
+
+			implicitly = !explicit[pc]
+		case _OP_MATCH == i.op:
+
+			continue
+		default:
+
+			implicitly = !is_separator(pc)
+		}
+
+		if !implicitly {
+
+			continue
+		}
+
+		if i.out < n && -1 != twins[i.out] {
+
+			i.out = twins[i.out]
+		}
+
+		if _OP_SPLIT == i.op && -1 != twins[i.arg] {
+
+			i.arg = twins[i.arg]
+		}
+	}
+
+	for pc := 0; n != pc; pc++ {
+
+		p.insts[pc].explicit = _OP_CHAR == p.insts[pc].op && '.' == p.insts[pc].r && explicit[pc]
+	}
+}
+
+// Determines whether the program consists only of single-character
+// instructions and stars - the latter being a split whose out is an _OP_ANY
+// that loops back to it - and may therefore be matched by
+// program.match_simple().
+func is_simple_program(p *program) bool {
+
+	for pc := 0; len(p.insts) != pc; pc++ {
+
+		i := &p.insts[pc]
+
+		switch i.op {
+
+		case _OP_SPLIT:
+
+			if i.out != pc+1 || i.arg != pc+2 {
+
+				return false
+			}
+
+			if any := &p.insts[pc+1]; _OP_ANY != any.op || any.out != pc {
+
+				return false
+			}
+
+			pc++
+		case _OP_CHAR, _OP_ANY, _OP_RANGE, _OP_NOT_RANGE:
+
+			if i.out != pc+1 {
+
+				return false
+			}
+		case _OP_MATCH:
+		default:
+
+			return false
+		}
+	}
+
+	return true
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
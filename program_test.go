@@ -0,0 +1,811 @@
+package shwild
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal functions
+ */
+
+func compile_test_program(t *testing.T, pattern string, flags uint64) *program {
+
+	matchers, err := parse_matchers(pattern, flags, _PATH_SEPARATOR)
+	if err != nil {
+
+		t.Fatalf("Failed to parse pattern '%s': %v", pattern, err)
+	}
+
+	return compile_program(matchers, flags, _PATH_SEPARATOR)
+}
+
+// Matches s against p using the state-set simulation, regardless of
+// whether p is simple.
+func match_nfa(p *program, s string) bool {
+
+	simple := p.simple
+
+	p.simple = false
+
+	defer func() {
+
+		p.simple = simple
+	}()
+
+	return p.match(s)
+}
+
+// Matches s against the capturing program p by simulating, in the manner
+// of Pike, threads that each carry their own captures.
+func match_captures_pike(p *program, s string) ([]int, bool) {
+
+	type pike_thread struct {
+		pc   int
+		caps []int
+	}
+
+	var add func(list []pike_thread, visited *state_set, pc, pos int, caps []int) []pike_thread
+
+	add = func(list []pike_thread, visited *state_set, pc, pos int, caps []int) []pike_thread {
+
+		if visited.contains(pc) {
+
+			return list
+		}
+
+		visited.insert(pc)
+
+		switch i := &p.insts[pc]; i.op {
+
+		case _OP_SPLIT:
+
+			list = add(list, visited, i.out, pos, caps)
+			list = add(list, visited, i.arg, pos, caps)
+		case _OP_JUMP:
+
+			list = add(list, visited, i.out, pos, caps)
+		case _OP_SAVE:
+
+			caps = append([]int(nil), caps...)
+			caps[i.arg] = pos
+
+			list = add(list, visited, i.out, pos, caps)
+		default:
+
+			list = append(list, pike_thread{pc: pc, caps: caps})
+		}
+
+		return list
+	}
+
+	visited := make_state_set(len(p.insts))
+
+	caps := make([]int, 2+2*p.ncap)
+
+	for ix := range caps {
+
+		caps[ix] = -1
+	}
+
+	clist := add(nil, &visited, 0, 0, caps)
+
+	for i := 0; ; {
+
+		if len(s) == i {
+
+			for _, t := range clist {
+
+				if _OP_MATCH == p.insts[t.pc].op {
+
+					t.caps[0], t.caps[1] = 0, len(s)
+
+					return t.caps, true
+				}
+			}
+
+			return nil, false
+		}
+
+		c, w := decode_char(s[i:])
+		protected := is_protected_period_at(p, s, i, c)
+
+		visited.clear()
+
+		var nlist []pike_thread
+
+		for _, t := range clist {
+
+			if p.consumes(t.pc, c, protected) {
+
+				nlist = add(nlist, &visited, p.insts[t.pc].out, i+w, t.caps)
+			}
+		}
+
+		clist = nlist
+		i += w
+	}
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * tests
+ */
+
+func Test_program_simple_and_nfa_agree(t *testing.T) {
+
+	patterns := []string{
+		"a",
+		"abc",
+		"?",
+		"a?c",
+		"*",
+		"a*",
+		"*a",
+		"a*c",
+		"*a*",
+		"a*b*c",
+		"*a*a*a*b",
+		"[ab]*",
+		"*[^ab]",
+		"?*?",
+		"*?*?*",
+		"[a-c]?[^b]*a",
+		"é*?",
+	}
+
+	subjects := []string{
+		"",
+		"a",
+		"b",
+		"ab",
+		"abc",
+		"aab",
+		"abab",
+		"aaaab",
+		"cba",
+		"abcabc",
+		"caba",
+		"é",
+		"ééx",
+		"a\xffc",
+	}
+
+	for _, pattern := range patterns {
+
+		for _, flags := range []uint64{0, IgnoreCase} {
+
+			p := compile_test_program(t, pattern, flags)
+
+			if !p.simple {
+
+				t.Errorf("pattern '%s' should be simple", pattern)
+			}
+
+			for _, s := range subjects {
+
+				if expected, actual := p.match(s), match_nfa(p, s); expected != actual {
+
+					t.Errorf("pattern '%s' (flags=0x%x) against '%s': simple returned %v; NFA returned %v", pattern, flags, s, expected, actual)
+				}
+			}
+		}
+	}
+}
+
+func Test_matchers_size_agrees_with_program(t *testing.T) {
+
+	for _, tc := range []struct {
+		pattern string
+		flags   uint64
+	}{
+		{"abc", 0},
+		{"a?c*", 0},
+		{"[a-z]*.[!c]", 0},
+		{"a\\3[bc]\\2-5?\\-4d\\1-", AllowRangeQuantification},
+		{"*.{go,m{d,arkdown},}", AllowBraceAlternation},
+		{"{a\\2-3,b}x\\3", AllowBraceAlternation | AllowRangeQuantification},
+		{"a/**/b/**", PathMode},
+		{"**", PathMode},
+		{"\xff\xfeé", 0},
+	} {
+
+		matchers, err := parse_matchers(tc.pattern, tc.flags, _PATH_SEPARATOR)
+		if err != nil {
+
+			t.Fatalf("Failed to parse pattern '%s': %v", tc.pattern, err)
+		}
+
+		p := compile_program(matchers, tc.flags, _PATH_SEPARATOR)
+
+		// a wilddirs matcher may compile to fewer instructions than its size
+
+		if size := matchers_size(matchers, program_limit(tc.pattern)); size < len(p.insts) || (0 == (PathMode&tc.flags) && size != len(p.insts)) {
+
+			t.Errorf("pattern '%s' has size %d, but compiles to %d instructions", tc.pattern, size, len(p.insts))
+		}
+	}
+}
+
+func Test_program_simple_and_nfa_agree_randomly(t *testing.T) {
+
+	const pattern_chars = "ab?*"
+	const subject_chars = "abc"
+
+	rng := rand.New(rand.NewSource(1))
+
+	random_string := func(chars string, max int) string {
+
+		b := make([]byte, rng.Intn(max+1))
+
+		for i := range b {
+
+			b[i] = chars[rng.Intn(len(chars))]
+		}
+
+		return string(b)
+	}
+
+	for n := 0; 10000 != n; n++ {
+
+		pattern := random_string(pattern_chars, 8)
+
+		if 0 == len(pattern) {
+
+			continue
+		}
+
+		p := compile_test_program(t, pattern, 0)
+		s := random_string(subject_chars, 10)
+
+		if expected, actual := p.match(s), match_nfa(p, s); expected != actual {
+
+			t.Fatalf("pattern '%s' against '%s': simple returned %v; NFA returned %v", pattern, s, expected, actual)
+		}
+	}
+}
+
+func Test_program_simple_and_nfa_agree_randomly_with_path_flags(t *testing.T) {
+
+	const pattern_chars = "a./?*"
+	const subject_chars = "ab./"
+
+	rng := rand.New(rand.NewSource(1))
+
+	random_string := func(chars string, max int) string {
+
+		b := make([]byte, rng.Intn(max+1))
+
+		for i := range b {
+
+			b[i] = chars[rng.Intn(len(chars))]
+		}
+
+		return string(b)
+	}
+
+	for n := 0; 10000 != n; n++ {
+
+		pattern := random_string(pattern_chars, 8)
+
+		if 0 == len(pattern) {
+
+			continue
+		}
+
+		for _, flags := range []uint64{PathMode, ExplicitLeadingPeriod, PathMode | ExplicitLeadingPeriod} {
+
+			p := compile_test_program(t, pattern, flags)
+			s := random_string(subject_chars, 10)
+
+			if expected, actual := p.match(s), match_nfa(p, s); expected != actual {
+
+				t.Fatalf("pattern '%s' (flags=0x%x) against '%s': simple returned %v; NFA returned %v", pattern, flags, s, expected, actual)
+			}
+		}
+	}
+}
+
+func Test_program_captures_agree_randomly(t *testing.T) {
+
+	const pattern_chars = "ab/?*[]{,}"
+	const subject_chars = "ab/"
+
+	rng := rand.New(rand.NewSource(1))
+
+	random_string := func(chars string, max int) string {
+
+		b := make([]byte, rng.Intn(max+1))
+
+		for i := range b {
+
+			b[i] = chars[rng.Intn(len(chars))]
+		}
+
+		return string(b)
+	}
+
+	for n := 0; 10000 != n; n++ {
+
+		pattern := random_string(pattern_chars, 8)
+		flags := uint64(AllowBraceAlternation)
+
+		if 0 == rng.Intn(2) {
+
+			flags |= PathMode
+		}
+
+		matchers, err := parse_matchers(pattern, flags, _PATH_SEPARATOR)
+
+		if nil != err || 0 == len(matchers) {
+
+			continue
+		}
+
+		p := compile_program(matchers, flags, _PATH_SEPARATOR)
+		s := random_string(subject_chars, 10)
+
+		caps, matched := p.capturing_program().match_captures(s)
+
+		if expected := p.match(s); expected != matched {
+
+			t.Fatalf("pattern '%s' (flags=0x%x) against '%s': match() returned %v; match_captures() returned %v", pattern, flags, s, expected, matched)
+		}
+
+		if expected, _ := match_captures_pike(p.capturing_program(), s); !slices.Equal(expected, caps) {
+
+			t.Fatalf("pattern '%s' (flags=0x%x) against '%s': match_captures() obtained %v; %v expected", pattern, flags, s, caps, expected)
+		}
+
+		if !matched {
+
+			continue
+		}
+
+		// the captures are ordered, and do not overlap
+
+		prev := 0
+
+		for i := 2; len(caps) != i; i += 2 {
+
+			if caps[i] < 0 {
+
+				continue
+			}
+
+			if caps[i] < prev || caps[i+1] < caps[i] {
+
+				t.Fatalf("pattern '%s' (flags=0x%x) against '%s': invalid captures %v", pattern, flags, s, caps)
+			}
+
+			prev = caps[i+1]
+		}
+	}
+}
+
+func Test_program_match_captures_is_linear(t *testing.T) {
+
+	// a pattern of n wildcards against a subject of 2n characters, for
+	// which each of O(n) threads would carry O(n) captures at each of O(n)
+	// characters
+
+	const n = 400
+
+	p := compile_test_program(t, strings.Repeat("?*", n)+"a", 0).capturing_program()
+	s := strings.Repeat("b", 2*n) + "a"
+
+	// the first star is greedy, leaving one character for each ?
+
+	if caps, matched := p.match_captures(s); !matched || 1 != caps[4] || 1+n != caps[5] {
+
+		t.Fatalf("match_captures() obtained %v", matched)
+	}
+
+	// the allocations are of the captures and of the states - of which the
+	// largest is the viable states, of len(s) * len(pattern) bits - rather
+	// than of captures for each thread at each character
+
+	allocs := testing.AllocsPerRun(3, func() {
+
+		p.match_captures(s)
+	})
+
+	if allocs > 100 {
+
+		t.Errorf("match_captures() made %v allocations", allocs)
+	}
+}
+
+func Test_program_explicit_periods_agree_with_expansions_randomly(t *testing.T) {
+
+	const atom_chars = "a./*?"
+	const subject_chars = "a./"
+
+	const flags = PathMode | ExplicitLeadingPeriod | AllowBraceAlternation
+
+	rng := rand.New(rand.NewSource(1))
+
+	random_string := func(chars string, max int) string {
+
+		b := make([]byte, rng.Intn(max+1))
+
+		for i := range b {
+
+			b[i] = chars[rng.Intn(len(chars))]
+		}
+
+		return string(b)
+	}
+
+	for n := 0; 5000 != n; n++ {
+
+		// a sequence of characters and alternations of two branches, along
+		// with each of the patterns into which it expands
+
+		pattern := ""
+		expansions := []string{""}
+
+		for k := rng.Intn(4); k >= 0; k-- {
+
+			var branches []string
+
+			if 0 == rng.Intn(2) {
+
+				branches = []string{random_string(atom_chars, 2)}
+
+				pattern += branches[0]
+			} else {
+
+				branches = []string{random_string(atom_chars, 2), random_string(atom_chars, 2)}
+
+				pattern += "{" + branches[0] + "," + branches[1] + "}"
+			}
+
+			var expanded []string
+
+			for _, e := range expansions {
+
+				for _, branch := range branches {
+
+					expanded = append(expanded, e+branch)
+				}
+			}
+
+			expansions = expanded
+		}
+
+		// an expansion in which stars become adjacent is of a different
+		// wildcard
+
+		if slices.ContainsFunc(expansions, func(e string) bool { return strings.Contains(e, "**") }) {
+
+			continue
+		}
+
+		cp, err := Compile(pattern, flags)
+
+		if nil != err {
+
+			continue
+		}
+
+		ps, _ := CompileSet([]string{pattern}, flags)
+
+		for k := 0; 10 != k; k++ {
+
+			s := random_string(subject_chars, 5)
+
+			expected := false
+
+			for _, e := range expansions {
+
+				if matched, _ := Match(e, s, flags); matched {
+
+					expected = true
+				}
+			}
+
+			if matched, _ := cp.Match(s); expected != matched {
+
+				t.Fatalf("pattern '%s' against '%s': Match() returned %v; %v expected, from expansions %q", pattern, s, matched, expected, expansions)
+			}
+
+			if _, matched := cp.FindSubmatchIndex(s); expected != matched {
+
+				t.Fatalf("pattern '%s' against '%s': FindSubmatchIndex() returned %v; %v expected", pattern, s, matched, expected)
+			}
+
+			if matched := 0 != len(ps.Match(s)); expected != matched {
+
+				t.Fatalf("pattern '%s' against '%s': PatternSet.Match() returned %v; %v expected", pattern, s, matched, expected)
+			}
+		}
+	}
+}
+
+func Test_program_dirs_wildcards_agree_with_expansions_randomly(t *testing.T) {
+
+	// each element is a character, or a separator, or a ** (as Z) that may
+	// be a whole segment
+
+	elements := []string{"", "a", "b", "/", "*", "Z", "a/", "/a", "Z/", "/Z", "a/Z", "Z/a"}
+
+	const subject_chars = "ab/"
+
+	const flags = PathMode | AllowBraceAlternation
+
+	rng := rand.New(rand.NewSource(1))
+
+	for n := 0; 5000 != n; n++ {
+
+		// a sequence of elements and alternations of two branches, along
+		// with each of the patterns into which it expands
+
+		pattern := ""
+		expansions := []string{""}
+
+		for k := rng.Intn(4); k >= 0; k-- {
+
+			var branches []string
+
+			if 0 == rng.Intn(3) {
+
+				branches = []string{elements[rng.Intn(len(elements))]}
+
+				pattern += branches[0]
+			} else {
+
+				branches = []string{elements[rng.Intn(len(elements))], elements[rng.Intn(len(elements))]}
+
+				pattern += "{" + branches[0] + "," + branches[1] + "}"
+			}
+
+			var expanded []string
+
+			for _, e := range expansions {
+
+				for _, branch := range branches {
+
+					expanded = append(expanded, e+branch)
+				}
+			}
+
+			expansions = expanded
+		}
+
+		// an expansion in which stars become adjacent is of a different
+		// wildcard, and one with an empty or redundant segment is not of
+		// interest; a ** that adjoins an alternation from outside, or is
+		// separated only by a separator from one in a further alternation,
+		// is not a whole segment for every branch (see PathMode)
+
+		if slices.ContainsFunc(expansions, func(e string) bool {
+
+			for _, s := range []string{"ZZ", "*Z", "Z*", "**", "Z/Z", "//"} {
+
+				if strings.Contains(e, s) {
+
+					return true
+				}
+			}
+
+			return false
+		}) {
+
+			continue
+		}
+
+		if strings.Contains(pattern, "}{") || strings.Contains(pattern, "}/{") || strings.Contains(pattern, "}Z") || strings.Contains(pattern, "Z{") {
+
+			continue
+		}
+
+		pattern = strings.ReplaceAll(pattern, "Z", "**")
+
+		for i := range expansions {
+
+			expansions[i] = strings.ReplaceAll(expansions[i], "Z", "**")
+		}
+
+		cp, err := Compile(pattern, flags)
+
+		if nil != err {
+
+			t.Fatalf("Compile('%s') failed: %v", pattern, err)
+		}
+
+		for k := 0; 10 != k; k++ {
+
+			b := make([]byte, rng.Intn(7))
+
+			for i := range b {
+
+				b[i] = subject_chars[rng.Intn(len(subject_chars))]
+			}
+
+			s := string(b)
+
+			expected := false
+
+			for _, e := range expansions {
+
+				if matched, _ := Match(e, s, flags); matched {
+
+					expected = true
+				}
+			}
+
+			if matched, _ := cp.Match(s); expected != matched {
+
+				t.Fatalf("pattern '%s' against '%s': Match() returned %v; %v expected, from expansions %q", pattern, s, matched, expected, expansions)
+			}
+		}
+	}
+}
+
+func Test_program_search_agrees_with_match_randomly(t *testing.T) {
+
+	const pattern_chars = "ab?*[]{,}"
+	const subject_chars = "abc"
+
+	rng := rand.New(rand.NewSource(1))
+
+	random_string := func(chars string, max int) string {
+
+		b := make([]byte, rng.Intn(max+1))
+
+		for i := range b {
+
+			b[i] = chars[rng.Intn(len(chars))]
+		}
+
+		return string(b)
+	}
+
+	for n := 0; 5000 != n; n++ {
+
+		pattern := random_string(pattern_chars, 6)
+
+		matchers, err := parse_matchers(pattern, AllowBraceAlternation, _PATH_SEPARATOR)
+
+		if nil != err || 0 == len(matchers) {
+
+			continue
+		}
+
+		p := compile_program(matchers, AllowBraceAlternation, _PATH_SEPARATOR)
+		s := random_string(subject_chars, 8)
+
+		for _, longest := range []bool{false, true} {
+
+			// the leftmost start, and the shortest or longest end, of the
+			// substrings that match
+
+			expected_start, expected_end, expected_ok := -1, -1, false
+
+			for start := 0; len(s) >= start && !expected_ok; start++ {
+
+				for end := start; len(s) >= end; end++ {
+
+					if p.match(s[start:end]) {
+
+						if !expected_ok || longest {
+
+							expected_start, expected_end, expected_ok = start, end, true
+						}
+					}
+				}
+			}
+
+			start, end, ok := p.search(s, 0, longest)
+
+			if expected_ok != ok || (ok && (expected_start != start || expected_end != end)) {
+
+				t.Fatalf("pattern '%s' against '%s' (longest=%v): search() returned %d, %d, %v; %d, %d, %v expected", pattern, s, longest, start, end, ok, expected_start, expected_end, expected_ok)
+			}
+		}
+	}
+}
+
+func Test_program_match_partial_agrees_with_match_randomly(t *testing.T) {
+
+	// patterns are composed of tokens that are matched only by the
+	// characters in subject_chars
+
+	tokens := []string{"a", "b", "?", "*", "[ab]", "[^a]", "{a,b}", "{,ab}", "b\\2"}
+
+	const subject_chars = "abc"
+
+	rng := rand.New(rand.NewSource(1))
+
+	random_string := func(chars string, max int) string {
+
+		b := make([]byte, rng.Intn(max+1))
+
+		for i := range b {
+
+			b[i] = chars[rng.Intn(len(chars))]
+		}
+
+		return string(b)
+	}
+
+	// all strings over subject_chars of up to the given length
+
+	var extensions func(n int) []string
+
+	extensions = func(n int) []string {
+
+		if 0 == n {
+
+			return []string{""}
+		}
+
+		r := []string{""}
+
+		for _, e := range extensions(n - 1) {
+
+			for _, ch := range subject_chars {
+
+				r = append(r, string(ch)+e)
+			}
+		}
+
+		return r
+	}
+
+	all_extensions := extensions(6)
+
+	const flags = AllowBraceAlternation | AllowRangeQuantification
+
+	for n := 0; 2000 != n; n++ {
+
+		var pattern string
+
+		for i := rng.Intn(4); 0 != i; i-- {
+
+			pattern += tokens[rng.Intn(len(tokens))]
+		}
+
+		matchers, err := parse_matchers(pattern, flags, _PATH_SEPARATOR)
+
+		if nil != err {
+
+			t.Fatalf("Failed to parse pattern '%s': %v", pattern, err)
+		}
+
+		if 0 == len(matchers) {
+
+			continue
+		}
+
+		p := compile_program(matchers, flags, _PATH_SEPARATOR)
+		prefix := random_string(subject_chars, 4)
+
+		expected := NoMatch
+
+		if p.match(prefix) {
+
+			expected = Matched
+		} else {
+
+			for _, e := range all_extensions {
+
+				if p.match(prefix + e) {
+
+					expected = NeedMore
+
+					break
+				}
+			}
+		}
+
+		if actual := p.match_partial(prefix); expected != actual {
+
+			t.Fatalf("pattern '%s' against prefix '%s': match_partial() returned %v; %v expected", pattern, prefix, actual, expected)
+		}
+	}
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
@@ -0,0 +1,289 @@
+// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2026
+// Matthew Wilson and Synesis Information Systems. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+/*
+ * Created: 18th October 2026
+ * Updated: 18th October 2026
+ */
+
+package shwild
+
+import (
+	"strings"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * API types
+ */
+
+// Renaming describes the renaming of one name, as obtained from Rename().
+type Renaming struct {
+	From string
+	To   string
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal types
+ */
+
+// template_part structure
+//
+// A part of a replacement template, which is either literal text or a
+// reference to a submatch.
+
+type template_part struct {
+	literal string
+	ref     int // the number of the submatch, or -1 for literal text
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * API functions
+ */
+
+// Replace matches s against the pattern and, if it matches, obtains the
+// expansion of template, in which #n and ${n} are replaced by the n-th
+// submatch - as obtained by FindSubmatch(), so that #0 is the whole of s
+// and #1 is the substring consumed by the first wildcard - and ## and $$
+// are replaced by # and $, respectively. A # or $ that does not begin a
+// reference is literal. A malformed template, or a reference to a wildcard
+// that is not in the pattern, is reported as a *TemplateError, regardless
+// of whether s matches.
+func (cp CompiledPattern) Replace(s, template string) (string, bool, error) {
+
+	parts, err := parse_template(template, cp.num_submatches())
+
+	if nil != err {
+
+		return "", false, err
+	}
+
+	submatches, matched := cp.FindSubmatch(s)
+
+	if !matched {
+
+		return "", false, nil
+	}
+
+	return expand_template(parts, submatches), true, nil
+}
+
+// Rename matches each of names against pattern, subject to additional
+// arguments that moderate behaviour, and obtains the renaming - by way of
+// CompiledPattern.Replace() - of each name that matches and would be
+// changed, in the manner of mmv.
+//
+// No renamings are obtained if any would collide, which is reported as a
+// *CollisionError: where more than one name would be renamed to the same
+// destination, or where a destination is already one of names (even if
+// that name is itself renamed), so that the renamings may be performed in
+// any order.
+func Rename(pattern, template string, names []string, args ...any) ([]Renaming, error) {
+
+	cp, err := Compile(pattern, args...)
+
+	if nil != err {
+
+		return nil, err
+	}
+
+	parts, err := parse_template(template, cp.num_submatches())
+
+	if nil != err {
+
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(names))
+
+	for _, name := range names {
+
+		existing[name] = true
+	}
+
+	var renamings []Renaming
+
+	sources := make(map[string][]string)
+
+	for _, name := range names {
+
+		submatches, matched := cp.FindSubmatch(name)
+
+		if !matched {
+
+			continue
+		}
+
+		to := expand_template(parts, submatches)
+
+		if to == name {
+
+			continue
+		}
+
+		renamings = append(renamings, Renaming{From: name, To: to})
+		sources[to] = append(sources[to], name)
+	}
+
+	for _, r := range renamings {
+
+		if from := sources[r.To]; 1 != len(from) {
+
+			return nil, &CollisionError{To: r.To, From: from}
+		}
+
+		if existing[r.To] {
+
+			return nil, &CollisionError{To: r.To, From: []string{r.From}, Exists: true}
+		}
+	}
+
+	return renamings, nil
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal functions
+ */
+
+// Obtains the number of submatches - the whole match and each wildcard -
+// of the compiled pattern.
+func (cp CompiledPattern) num_submatches() int {
+
+	switch cp.behaviour {
+
+	case _PB_AllWildPattern:
+
+		return 1 + len(cp.Pattern)
+	case _PB_RegularPattern:
+
+		return 1 + cp.prog.capturing_program().ncap
+	default:
+
+		return 1
+	}
+}
+
+// Parses the replacement template into its parts, each of whose references
+// must be to one of n submatches.
+func parse_template(template string, n int) ([]template_part, error) {
+
+	var parts []template_part
+	var literal strings.Builder
+
+	flush := func() {
+
+		if 0 != literal.Len() {
+
+			parts = append(parts, template_part{literal: literal.String(), ref: -1})
+			literal.Reset()
+		}
+	}
+
+	for ix := 0; len(template) != ix; {
+
+		ch := template[ix]
+
+		switch {
+
+		case ('#' == ch || '$' == ch) && strings.HasPrefix(template[ix+1:], string(ch)):
+
+			literal.WriteByte(ch)
+			ix += 2
+		case '#' == ch && ix+1 != len(template) && is_digit(template[ix+1]):
+
+			end := ix + 1
+
+			for end != len(template) && is_digit(template[end]) {
+
+				end++
+			}
+
+			ref, ok := parse_count(template[ix+1 : end])
+
+			if !ok || ref >= n {
+
+				return nil, make_template_error(template, ix, ReferenceOutOfRange)
+			}
+
+			flush()
+			parts = append(parts, template_part{ref: ref})
+			ix = end
+		case '$' == ch && strings.HasPrefix(template[ix+1:], "{"):
+
+			end := strings.IndexByte(template[ix:], '}')
+
+			if end < 0 {
+
+				return nil, make_template_error(template, ix, UnterminatedReference)
+			}
+
+			end += ix
+
+			digits := template[ix+2 : end]
+
+			if !is_number(digits) {
+
+				return nil, make_template_error(template, ix, InvalidReference)
+			}
+
+			ref, ok := parse_count(digits)
+
+			if !ok || ref >= n {
+
+				return nil, make_template_error(template, ix, ReferenceOutOfRange)
+			}
+
+			flush()
+			parts = append(parts, template_part{ref: ref})
+			ix = end + 1
+		default:
+
+			literal.WriteByte(ch)
+			ix++
+		}
+	}
+
+	flush()
+
+	return parts, nil
+}
+
+// Determines whether s is a non-empty sequence of decimal digits.
+func is_number(s string) bool {
+
+	for i := 0; len(s) != i; i++ {
+
+		if !is_digit(s[i]) {
+
+			return false
+		}
+	}
+
+	return 0 != len(s)
+}
+
+func is_digit(ch byte) bool {
+
+	return '0' <= ch && ch <= '9'
+}
+
+func expand_template(parts []template_part, submatches []string) string {
+
+	var sb strings.Builder
+
+	for _, part := range parts {
+
+		if part.ref < 0 {
+
+			sb.WriteString(part.literal)
+		} else {
+
+			sb.WriteString(submatches[part.ref])
+		}
+	}
+
+	return sb.String()
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
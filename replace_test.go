@@ -0,0 +1,158 @@
+package shwild_test
+
+import (
+	shwild "github.com/synesissoftware/shwild.Go"
+
+	"errors"
+	"slices"
+	"testing"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * tests
+ */
+
+func Test_CompiledPattern_Replace(t *testing.T) {
+
+	for _, tc := range []struct {
+		pattern  string
+		flags    int
+		s        string
+		template string
+		expected string
+		matched  bool
+	}{
+		{"*.jpeg", 0, "photo.jpeg", "#1.jpg", "photo.jpg", true},
+		{"*.jpeg", 0, "photo.jpeg", "${1}.jpg", "photo.jpg", true},
+		{"*.jpeg", 0, "photo.png", "#1.jpg", "", false},
+		{"*-*", 0, "a-b-c", "#2-#1", "c-a-b", true},
+		{"img_???.*", 0, "img_042.png", "#4/#1#2#3", "png/042", true},
+		{"*.txt", 0, "a.txt", "#0.bak", "a.txt.bak", true},
+		{"*.txt", 0, "a.txt", "##1 $$1 #x $x ${1}0", "#1 $1 #x $x a0", true},
+		{"*.txt", 0, "a.txt", "", "", true},
+		{"*", 0, "abc", "[#1]", "[abc]", true},
+		{"src/**/*.go", shwild.PathMode, "src/a/b/main.go", "dst/#1#2_test.go", "dst/a/b/main_test.go", true},
+		{"*.{jpg,jpeg}", shwild.AllowBraceAlternation, "a.jpeg", "#1.JPG", "a.JPG", true},
+	} {
+
+		cp, err := shwild.Compile(tc.pattern, tc.flags)
+		if err != nil {
+
+			t.Errorf("Failed to compile pattern '%s'", tc.pattern)
+
+			continue
+		}
+
+		r, matched, err := cp.Replace(tc.s, tc.template)
+
+		if nil != err || tc.matched != matched || tc.expected != r {
+
+			t.Errorf("Replace('%s', '%s') against pattern '%s' returned '%s', %v, %v; '%s', %v, nil expected", tc.s, tc.template, tc.pattern, r, matched, err, tc.expected, tc.matched)
+		}
+	}
+}
+
+func Test_CompiledPattern_Replace_with_invalid_templates(t *testing.T) {
+
+	cp, err := shwild.Compile("*.[ch]")
+	if err != nil {
+
+		t.Fatalf("Failed to compile pattern '%s'", "*.[ch]")
+	}
+
+	for _, tc := range []struct {
+		template string
+		kind     shwild.TemplateErrorKind
+		offset   int
+	}{
+		{"#3", shwild.ReferenceOutOfRange, 0},
+		{"a.${3}", shwild.ReferenceOutOfRange, 2},
+		{"${1", shwild.UnterminatedReference, 0},
+		{"${}", shwild.InvalidReference, 0},
+		{"${a}", shwild.InvalidReference, 0},
+		{"#99999", shwild.ReferenceOutOfRange, 0},
+	} {
+
+		// the template is checked even if the subject does not match
+
+		for _, s := range []string{"main.c", "main.go"} {
+
+			r, matched, err := cp.Replace(s, tc.template)
+
+			var te *shwild.TemplateError
+
+			if !errors.As(err, &te) || tc.kind != te.Kind || tc.offset != te.Offset || matched || "" != r {
+
+				t.Errorf("Replace('%s', '%s') returned '%s', %v, %v; %v at offset %d expected", s, tc.template, r, matched, err, tc.kind, tc.offset)
+			}
+
+			if !errors.Is(err, tc.kind) {
+
+				t.Errorf("Replace('%s', '%s') returned %v, which is not %v", s, tc.template, err, tc.kind)
+			}
+		}
+	}
+}
+
+func Test_Rename(t *testing.T) {
+
+	names := []string{"a.jpeg", "b.jpeg", "c.png", "d.jpg"}
+
+	renamings, err := shwild.Rename("*.jpeg", "#1.jpg", names)
+
+	expected := []shwild.Renaming{
+		{From: "a.jpeg", To: "a.jpg"},
+		{From: "b.jpeg", To: "b.jpg"},
+	}
+
+	if nil != err || !slices.Equal(expected, renamings) {
+
+		t.Errorf("Rename() returned %v, %v; %v expected", renamings, err, expected)
+	}
+
+	// names that would be unchanged are not renamed
+
+	renamings, err = shwild.Rename("*", "#1", names)
+
+	if nil != err || 0 != len(renamings) {
+
+		t.Errorf("Rename() returned %v, %v; no renamings expected", renamings, err)
+	}
+}
+
+func Test_Rename_with_collisions(t *testing.T) {
+
+	// more than one name to the same destination
+
+	renamings, err := shwild.Rename("*.*", "#1.txt", []string{"a.c", "b.c", "a.h"})
+
+	var ce *shwild.CollisionError
+
+	if !errors.As(err, &ce) || "a.txt" != ce.To || !slices.Equal([]string{"a.c", "a.h"}, ce.From) || ce.Exists || nil != renamings {
+
+		t.Errorf("Rename() returned %v, %v; collision of 'a.c' and 'a.h' expected", renamings, err)
+	}
+
+	// a destination that already exists
+
+	renamings, err = shwild.Rename("*.jpeg", "#1.jpg", []string{"a.jpeg", "a.jpg"})
+
+	if !errors.As(err, &ce) || "a.jpg" != ce.To || !slices.Equal([]string{"a.jpeg"}, ce.From) || !ce.Exists || nil != renamings {
+
+		t.Errorf("Rename() returned %v, %v; collision with existing 'a.jpg' expected", renamings, err)
+	}
+
+	// pattern and template errors
+
+	if _, err = shwild.Rename("[abc", "#1", nil); !errors.Is(err, shwild.UnterminatedRange) {
+
+		t.Errorf("Rename() returned %v; %v expected", err, shwild.UnterminatedRange)
+	}
+
+	if _, err = shwild.Rename("*", "#2", nil); !errors.Is(err, shwild.ReferenceOutOfRange) {
+
+		t.Errorf("Rename() returned %v; %v expected", err, shwild.ReferenceOutOfRange)
+	}
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
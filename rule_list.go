@@ -0,0 +1,255 @@
+// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2026
+// Matthew Wilson and Synesis Information Systems. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+/*
+ * Created: 18th October 2026
+ * Updated: 18th October 2026
+ */
+
+package shwild
+
+import (
+	"errors"
+	"strings"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * API types
+ */
+
+// Rule is a single rule of a RuleList, as parsed from a line such as
+// "!/build/".
+type Rule struct {
+	Pattern  string // The pattern, without any !, leading / or trailing /
+	Negate   bool   // The rule re-includes what earlier rules matched
+	DirOnly  bool   // The rule applies only to directories
+	Anchored bool   // The rule matches the whole path, not just the name
+	Line     int    // The number of the line, from 1, of the rule
+	cp       CompiledPattern
+}
+
+// RuleList is an ordered list of rules, in the manner of .gitignore, that
+// determines whether a path is matched, as follows:
+//
+//   - the last rule that matches a path decides whether it is matched: a
+//     rule beginning with ! (a negated rule) un-matches it;
+//   - a rule ending with / matches only a directory;
+//   - a rule containing / other than at its end (including a leading /,
+//     which is otherwise ignored) is anchored, and matches the whole path;
+//     any other matches the name (the last element) of a path at any
+//     depth;
+//   - a path is matched if any directory that contains it is matched,
+//     regardless of any later rule, since a tool does not descend into a
+//     matched directory.
+//
+// Each pattern is compiled in path mode, so that * and ? do not match /,
+// and ** matches any number of directories, except that - as in git - a
+// trailing /** matches everything beneath a directory, but not the
+// directory itself. A blank line, or one beginning
+// with #, is not a rule; \# and \! begin a rule with a literal # or !.
+type RuleList struct {
+	Rules []Rule
+}
+
+// Match determines whether the path - which is relative to the root of the
+// rules, and uses / as its separator - is matched, where isDir indicates
+// whether it is a directory.
+func (rl RuleList) Match(path string, isDir bool) bool {
+
+	// each containing directory
+
+	for ix := 0; len(path) != ix; ix++ {
+
+		if _PATH_SEPARATOR == path[ix] && 0 != ix {
+
+			if rl.decide(path[:ix], true) {
+
+				return true
+			}
+		}
+	}
+
+	return rl.decide(path, isDir)
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * API functions
+ */
+
+// NewRuleList parses each of lines into a rule, subject to additional
+// arguments that moderate behaviour - which apply to every pattern, in
+// addition to PathMode - and obtains the list of rules.
+//
+// A line whose pattern is invalid is reported as a *RuleError, and the
+// errors of all such lines are returned together (as by errors.Join()),
+// along with the list of the valid rules, which the caller may choose to
+// use, as git does.
+func NewRuleList(lines []string, args ...any) (RuleList, error) {
+
+	o, err := parse_options_(args...)
+
+	if nil == err {
+
+		err = o.require_slash_separator()
+	}
+
+	if nil != err {
+
+		return RuleList{}, err
+	}
+
+	flags := o.flags | PathMode
+
+	var rl RuleList
+	var errs []error
+
+	for ix, line := range lines {
+
+		if err := rl.add_line(ix+1, line, flags); nil != err {
+
+			errs = append(errs, err)
+		}
+	}
+
+	return rl, errors.Join(errs...)
+}
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal functions
+ */
+
+// Parses the line, numbered n, and appends its rule, if any, to the list.
+func (rl *RuleList) add_line(n int, line string, flags uint64) error {
+
+	r, ok, err := parse_rule(line, flags)
+
+	if nil != err {
+
+		return &RuleError{Line: n, Text: line, Err: err}
+	}
+
+	if ok {
+
+		r.Line = n
+		rl.Rules = append(rl.Rules, r)
+	}
+
+	return nil
+}
+
+// Determines whether the path is matched by the rules, without regard to
+// the directories that contain it.
+func (rl RuleList) decide(path string, is_dir bool) bool {
+
+	name := path[strings.LastIndexByte(path, _PATH_SEPARATOR)+1:]
+
+	for ix := len(rl.Rules) - 1; ix >= 0; ix-- {
+
+		r := &rl.Rules[ix]
+
+		if r.DirOnly && !is_dir {
+
+			continue
+		}
+
+		subject := name
+
+		if r.Anchored {
+
+			subject = path
+		}
+
+		if matched, _ := r.cp.Match(subject); matched {
+
+			return !r.Negate
+		}
+	}
+
+	return false
+}
+
+// Parses the line into a rule, determining whether it is one.
+func parse_rule(line string, flags uint64) (Rule, bool, error) {
+
+	line = trim_trailing_spaces(line)
+
+	if 0 == len(line) || '#' == line[0] {
+
+		return Rule{}, false, nil
+	}
+
+	var r Rule
+
+	if '!' == line[0] {
+
+		r.Negate = true
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+
+		r.DirOnly = true
+		line = line[:len(line)-1]
+	}
+
+	if strings.HasPrefix(line, "/") {
+
+		r.Anchored = true
+		line = line[1:]
+	} else if strings.Contains(line, "/") {
+
+		r.Anchored = true
+	}
+
+	if 0 == len(line) {
+
+		return Rule{}, false, nil
+	}
+
+	// a trailing /** matches only what is beneath the directory
+
+	pattern := line
+
+	if strings.HasSuffix(pattern, "/**") {
+
+		pattern += "/*"
+	}
+
+	cp, err := Compile(pattern, flags)
+
+	if nil != err {
+
+		return Rule{}, false, err
+	}
+
+	r.Pattern = line
+	r.cp = cp
+
+	return r, true, nil
+}
+
+// Removes trailing spaces from the line, other than one that is escaped.
+func trim_trailing_spaces(line string) string {
+
+	trimmed := strings.TrimRight(line, " ")
+
+	if len(trimmed) == len(line) {
+
+		return line
+	}
+
+	// an odd number of backslashes escapes the first space
+
+	n := len(trimmed) - len(strings.TrimRight(trimmed, "\\"))
+
+	if 1 == n%2 {
+
+		return line[:len(trimmed)+1]
+	}
+
+	return trimmed
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
@@ -0,0 +1,167 @@
+package shwild_test
+
+import (
+	shwild "github.com/synesissoftware/shwild.Go"
+
+	"errors"
+	"testing"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * tests
+ */
+
+func Test_RuleList_Match(t *testing.T) {
+
+	rl, err := shwild.NewRuleList([]string{
+		"# build outputs",
+		"*.log",
+		"!important.log",
+		"build/",
+		"",
+		"/TODO",
+		"docs/*.html",
+		"**/tmp/**",
+		"*.o",
+		"!/keep/*.o",
+	})
+	if err != nil {
+
+		t.Fatalf("Failed to create rule list: %v", err)
+	}
+
+	if expected, actual := 8, len(rl.Rules); expected != actual {
+
+		t.Fatalf("rule list has %d rules; %d expected", actual, expected)
+	}
+
+	for _, tc := range []struct {
+		path     string
+		is_dir   bool
+		expected bool
+	}{
+		{"debug.log", false, true},
+		{"logs/debug.log", false, true},
+		{"important.log", false, false},
+		{"logs/important.log", false, false},
+		{"build", true, true},
+		{"build", false, false},
+		{"src/build", true, true},
+		{"build/main.o", false, true},
+		{"build/important.log", false, true},
+		{"TODO", false, true},
+		{"src/TODO", false, false},
+		{"docs/index.html", false, true},
+		{"docs/api/index.html", false, false},
+		{"a/tmp/b/c.txt", false, true},
+		{"main.o", false, true},
+		{"keep/main.o", false, false},
+		{"keep/sub/main.o", false, true},
+		{"main.go", false, false},
+		{"# build outputs", false, false},
+	} {
+
+		if actual := rl.Match(tc.path, tc.is_dir); tc.expected != actual {
+
+			t.Errorf("Match('%s', %v) returned %v; %v expected", tc.path, tc.is_dir, actual, tc.expected)
+		}
+	}
+}
+
+func Test_RuleList_Match_with_trailing_dirs_wildcard(t *testing.T) {
+
+	rl, err := shwild.NewRuleList([]string{
+		"logs/**",
+		"!logs/keep.txt",
+	})
+	if err != nil {
+
+		t.Fatalf("Failed to create rule list: %v", err)
+	}
+
+	for _, tc := range []struct {
+		path     string
+		is_dir   bool
+		expected bool
+	}{
+		{"logs", true, false},
+		{"logs", false, false},
+		{"logs/debug.txt", false, true},
+		{"logs/keep.txt", false, false},
+		{"logs/a", true, true},
+		{"logs/a/keep.txt", false, true},
+		{"src/logs/debug.txt", false, false},
+	} {
+
+		if actual := rl.Match(tc.path, tc.is_dir); tc.expected != actual {
+
+			t.Errorf("Match('%s', %v) returned %v; %v expected", tc.path, tc.is_dir, actual, tc.expected)
+		}
+	}
+}
+
+func Test_RuleList_Match_with_escapes(t *testing.T) {
+
+	rl, _ := shwild.NewRuleList([]string{
+		"\\#notes",
+		"\\!bang",
+		"space\\ ",
+		"trailing   ",
+	})
+
+	for _, tc := range []struct {
+		path     string
+		expected bool
+	}{
+		{"#notes", true},
+		{"!bang", true},
+		{"bang", false},
+		{"space ", true},
+		{"space", false},
+		{"trailing", true},
+		{"trailing ", false},
+	} {
+
+		if actual := rl.Match(tc.path, false); tc.expected != actual {
+
+			t.Errorf("Match('%s') returned %v; %v expected", tc.path, actual, tc.expected)
+		}
+	}
+}
+
+func Test_RuleList_Match_with_IgnoreCase(t *testing.T) {
+
+	rl, _ := shwild.NewRuleList([]string{"*.LOG"}, shwild.IgnoreCase)
+
+	if !rl.Match("dir/debug.log", false) {
+
+		t.Errorf("Match() failed to ignore case")
+	}
+}
+
+func Test_RuleList_Rules(t *testing.T) {
+
+	rl, _ := shwild.NewRuleList([]string{"!/build/", "a/b"})
+
+	if r := rl.Rules[0]; "build" != r.Pattern || !r.Negate || !r.DirOnly || !r.Anchored {
+
+		t.Errorf("rule 0 is %+v", r)
+	}
+
+	if r := rl.Rules[1]; "a/b" != r.Pattern || r.Negate || r.DirOnly || !r.Anchored {
+
+		t.Errorf("rule 1 is %+v", r)
+	}
+}
+
+func Test_NewRuleList_with_invalid_pattern(t *testing.T) {
+
+	_, err := shwild.NewRuleList([]string{"*.log", "![abc/"})
+
+	if !errors.Is(err, shwild.UnterminatedRange) {
+
+		t.Errorf("NewRuleList() returned %v; %v expected", err, shwild.UnterminatedRange)
+	}
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
@@ -0,0 +1,130 @@
+// Copyright 2005-2012, Matthew Wilson and Sean Kelly. Copyright 2018-2026
+// Matthew Wilson and Synesis Information Systems. All rights reserved. Use
+// of this source code is governed by a BSD-style license that can be found
+// in the LICENSE file.
+
+/*
+ * Created: 18th October 2026
+ * Updated: 18th October 2026
+ */
+
+package shwild
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * internal types
+ */
+
+// rune_interval structure
+//
+// The closed interval of characters [lo, hi].
+
+type rune_interval struct {
+	lo rune
+	hi rune
+}
+
+// rune_set structure
+//
+// The characters of a range, represented as sorted, non-overlapping,
+// non-adjacent intervals - so that a continuum of any width occupies a
+// single interval - with a bitmap of the ASCII members, so that
+// membership is O(1) for ASCII and O(log n) otherwise.
+
+type rune_set struct {
+	ascii     [2]uint64
+	intervals []rune_interval
+}
+
+func (rs rune_set) String() string {
+
+	var sb strings.Builder
+
+	for _, iv := range rs.intervals {
+
+		if iv.lo == iv.hi {
+
+			fmt.Fprintf(&sb, "%q", iv.lo)
+		} else {
+
+			fmt.Fprintf(&sb, "%q-%q", iv.lo, iv.hi)
+		}
+	}
+
+	return sb.String()
+}
+
+// Adds the closed interval [lo, hi], which must not be reversed.
+func (rs *rune_set) add(lo, hi rune) {
+
+	rs.intervals = append(rs.intervals, rune_interval{lo, hi})
+}
+
+// Sorts and merges the intervals, and records the ASCII members. Must be
+// called after the last call to add() and before the first call to
+// contains().
+func (rs *rune_set) normalise() {
+
+	slices.SortFunc(rs.intervals, func(a, b rune_interval) int {
+
+		return int(a.lo) - int(b.lo)
+	})
+
+	merged := rs.intervals[:0]
+
+	for _, iv := range rs.intervals {
+
+		if n := len(merged); 0 != n && iv.lo <= merged[n-1].hi+1 {
+
+			merged[n-1].hi = max(merged[n-1].hi, iv.hi)
+		} else {
+
+			merged = append(merged, iv)
+		}
+	}
+
+	rs.intervals = slices.Clip(merged)
+
+	for _, iv := range rs.intervals {
+
+		for r := iv.lo; r <= iv.hi && r < utf8.RuneSelf; r++ {
+
+			rs.ascii[r/64] |= 1 << (r % 64)
+		}
+	}
+}
+
+func (rs *rune_set) contains(r rune) bool {
+
+	if r < utf8.RuneSelf {
+
+		return 0 <= r && 0 != rs.ascii[r/64]&(1<<(r%64))
+	}
+
+	// find the first interval that ends at or after r
+
+	lo, hi := 0, len(rs.intervals)
+
+	for lo < hi {
+
+		mid := int(uint(lo+hi) >> 1)
+
+		if rs.intervals[mid].hi < r {
+
+			lo = mid + 1
+		} else {
+
+			hi = mid
+		}
+	}
+
+	return lo < len(rs.intervals) && rs.intervals[lo].lo <= r
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */
@@ -0,0 +1,71 @@
+package shwild
+
+import (
+	"testing"
+)
+
+/* /////////////////////////////////////////////////////////////////////////
+ * tests
+ */
+
+func Test_rune_set_merges_intervals(t *testing.T) {
+
+	for _, tc := range []struct {
+		flags    uint64
+		data     string
+		expected []rune_interval
+	}{
+		{0, "abc", []rune_interval{{'a', 'c'}}},
+		{0, "cba", []rune_interval{{'a', 'c'}}},
+		{0, "a-ed-k", []rune_interval{{'a', 'k'}}},
+		{0, "0-9a-f", []rune_interval{{'0', '9'}, {'a', 'f'}}},
+		{0, "z-a", []rune_interval{{'a', 'z'}}},
+		{0, "a-Z", []rune_interval{{'A', 'Z'}, {'a', 'z'}}},
+		{0, "-a-c", []rune_interval{{'-', '-'}, {'a', 'c'}}},
+		{SuppressRangeContinuumSupport, "a-c", []rune_interval{{'-', '-'}, {'a', 'a'}, {'c', 'c'}}},
+		{0, "\u0000-\U0010ffff", []rune_interval{{0, 0x10ffff}}},
+	} {
+
+		rs := make_rune_set(tc.flags, tc.data)
+
+		if len(tc.expected) != len(rs.intervals) {
+
+			t.Errorf("make_rune_set(0x%x, %q) returned %v; %v expected", tc.flags, tc.data, rs.intervals, tc.expected)
+
+			continue
+		}
+
+		for i, iv := range tc.expected {
+
+			if iv != rs.intervals[i] {
+
+				t.Errorf("make_rune_set(0x%x, %q) returned %v; %v expected", tc.flags, tc.data, rs.intervals, tc.expected)
+
+				break
+			}
+		}
+	}
+}
+
+func Test_rune_set_contains(t *testing.T) {
+
+	rs := make_rune_set(0, "0-9a-fà-ÿ一-鿿\U0001f600")
+
+	for _, r := range []rune{'0', '9', 'a', 'f', 'à', 'ð', 'ÿ', '一', '漢', '鿿', '\U0001f600'} {
+
+		if !rs.contains(r) {
+
+			t.Errorf("%v does not contain %q", rs, r)
+		}
+	}
+
+	for _, r := range []rune{-1, '/', ':', 'g', '\u007f', 'ß', 'Ā', '䷿', 'ꀀ', '\U0001f601', 0x10ffff} {
+
+		if rs.contains(r) {
+
+			t.Errorf("%v contains %q", rs, r)
+		}
+	}
+}
+
+/* ///////////////////////////// end of file //////////////////////////// */